@@ -0,0 +1,154 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var homophones = [][]string{
+	{"allowed", "aloud"},
+	{"bare", "bear"},
+	{"berry", "bury"},
+	{"blew", "blue"},
+	{"brake", "break"},
+	{"buy", "by", "bye"},
+	{"ceiling", "sealing"},
+	{"cell", "sell"},
+	{"cereal", "serial"},
+	{"chili", "chilly"},
+	{"coarse", "course"},
+	{"dear", "deer"},
+	{"die", "dye"},
+	{"fair", "fare"},
+	{"flea", "flee"},
+	{"flour", "flower"},
+	{"grate", "great"},
+	{"hair", "hare"},
+	{"hear", "here"},
+	{"hole", "whole"},
+	{"hour", "our"},
+	{"idle", "idol"},
+	{"knew", "new"},
+	{"knight", "night"},
+	{"knot", "not"},
+	{"know", "no"},
+	{"lead", "led"},
+	{"made", "maid"},
+	{"mail", "male"},
+	{"meat", "meet"},
+	{"muscle", "mussel"},
+	{"one", "won"},
+	{"pair", "pare", "pear"},
+	{"patience", "patients"},
+	{"peace", "piece"},
+	{"pedal", "peddle"},
+	{"plain", "plane"},
+	{"praise", "prays", "preys"},
+	{"principal", "principle"},
+	{"rain", "reign", "rein"},
+	{"read", "red"},
+	{"right", "rite", "write"},
+	{"road", "rode"},
+	{"root", "route"},
+	{"sail", "sale"},
+	{"scene", "seen"},
+	{"sea", "see"},
+	{"son", "sun"},
+	{"stair", "stare"},
+	{"stationary", "stationery"},
+	{"steal", "steel"},
+	{"suite", "sweet"},
+	{"tail", "tale"},
+	{"their", "there"},
+	{"threw", "through"},
+	{"to", "too", "two"},
+	{"toe", "tow"},
+	{"vain", "vane", "vein"},
+	{"waist", "waste"},
+	{"wait", "weight"},
+	{"ware", "wear", "where"},
+	{"way", "weigh"},
+	{"weak", "week"},
+	{"which", "witch"},
+	{"wood", "would"},
+}
+
+var homophoneGroup = func() map[string]int {
+	groups := make(map[string]int)
+	for i, group := range homophones {
+		for _, word := range group {
+			groups[word] = i
+		}
+	}
+	return groups
+}()
+
+func ambiguityKeys(word string) []string {
+	runes := []rune(strings.ToLower(word))
+	keys := make([]string, 0, len(runes)+1)
+	for i, r := range runes {
+		runes[i] = utf8.RuneError
+		keys = append(keys, string(runes))
+		runes[i] = r
+	}
+	if i, ok := homophoneGroup[string(runes)]; ok {
+		keys = append(keys, "homophone:"+strconv.Itoa(i))
+	}
+	return keys
+}
+
+func filterAmbiguousWords(words []string, weights []uint64, exclude map[string]bool) ([]string, []uint64) {
+	seen := make(map[string]bool)
+	var kept []string
+	var keptWeights []uint64
+	for i, word := range words {
+		if exclude[strings.ToLower(word)] {
+			continue
+		}
+		keys := ambiguityKeys(word)
+		ambiguous := false
+		for _, key := range keys {
+			if seen[key] {
+				ambiguous = true
+				break
+			}
+		}
+		if ambiguous {
+			continue
+		}
+		for _, key := range keys {
+			seen[key] = true
+		}
+		kept = append(kept, word)
+		if weights != nil {
+			keptWeights = append(keptWeights, weights[i])
+		}
+	}
+	return kept, keptWeights
+}
+
+func readExcludeList(name string) (map[string]bool, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	exclude := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			exclude[strings.ToLower(word)] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return exclude, nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFilterAmbiguousWords(t *testing.T) {
+	tests := []struct {
+		words   []string
+		exclude map[string]bool
+		want    []string
+	}{
+		{[]string{"cat", "hat", "dog", "cot", "cart"}, nil, []string{"cat", "dog", "cart"}},
+		{[]string{"their", "apple", "there"}, nil, []string{"their", "apple"}},
+		{[]string{"Their", "There"}, nil, []string{"Their"}},
+		{[]string{"to", "two", "too"}, nil, []string{"to"}},
+		{[]string{"apple", "banana", "cherry"}, map[string]bool{"banana": true}, []string{"apple", "cherry"}},
+	}
+
+	for _, tt := range tests {
+		if got, _ := filterAmbiguousWords(tt.words, nil, tt.exclude); !slices.Equal(got, tt.want) {
+			t.Errorf("filterAmbiguousWords(%q): expected %q, but got %q", tt.words, tt.want, got)
+		}
+	}
+
+	words, weights := filterAmbiguousWords([]string{"cat", "hat", "dog"}, []uint64{1, 2, 3}, nil)
+	if !slices.Equal(words, []string{"cat", "dog"}) || !slices.Equal(weights, []uint64{1, 3}) {
+		t.Errorf("unexpected result %q %v", words, weights)
+	}
+}
+
+func TestReadExcludeList(t *testing.T) {
+	exclude, err := readExcludeList(writeTempFile(t, "Foo\n\n  bar \n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exclude) != 2 || !exclude["foo"] || !exclude["bar"] {
+		t.Errorf("unexpected result %v", exclude)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cions/genpass/internal/wordlists"
+)
+
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+func decodeMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("%w: expected 12, 15, 18, 21 or 24 words, but got %d", ErrInvalidMnemonic, len(words))
+	}
+
+	indices := make(map[string]int, len(wordlists.BIP39))
+	for i, word := range wordlists.BIP39 {
+		indices[word] = i
+	}
+
+	nbits := 11 * len(words)
+	buf := make([]byte, (nbits+7)/8)
+	for i, word := range words {
+		index, ok := indices[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, word)
+		}
+		for j := range 11 {
+			if index&(1<<(10-j)) != 0 {
+				pos := 11*i + j
+				buf[pos/8] |= 0x80 >> (pos % 8)
+			}
+		}
+	}
+
+	checksumBits := nbits / 33
+	entropy := buf[:(nbits-checksumBits)/8]
+	hash := sha256.Sum256(entropy)
+	for j := range checksumBits {
+		pos := len(entropy)*8 + j
+		got := buf[pos/8] & (0x80 >> (pos % 8))
+		want := hash[j/8] & (0x80 >> (j % 8))
+		if (got != 0) != (want != 0) {
+			return nil, fmt.Errorf("%w: checksum mismatch", ErrInvalidMnemonic)
+		}
+	}
+	return entropy, nil
+}
+
+func checkMnemonic(r io.Reader, w io.Writer) error {
+	input, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	entropy, err := decodeMnemonic(string(input))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "valid: %d-bit entropy %x\n", 8*len(entropy), entropy)
+	return nil
+}
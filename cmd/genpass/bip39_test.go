@@ -0,0 +1,58 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestDecodeMnemonic(t *testing.T) {
+	tests := []struct {
+		mnemonic string
+		entropy  string
+	}{
+		{
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			"00000000000000000000000000000000",
+		},
+		{
+			"legal winner thank year wave sausage worth useful legal winner thank yellow",
+			"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		},
+		{
+			"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+			"808080808080808080808080808080808080808080808080",
+		},
+		{
+			"Zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo vote\n",
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		},
+	}
+
+	for _, tt := range tests {
+		entropy, err := decodeMnemonic(tt.mnemonic)
+		if err != nil {
+			t.Errorf("decodeMnemonic(%q): unexpected error: %v", tt.mnemonic, err)
+		} else if got := hex.EncodeToString(entropy); got != tt.entropy {
+			t.Errorf("decodeMnemonic(%q): expected %v, but got %v", tt.mnemonic, tt.entropy, got)
+		}
+	}
+}
+
+func TestDecodeMnemonic_errors(t *testing.T) {
+	tests := []string{
+		"",
+		"abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon genpass",
+	}
+
+	for _, tt := range tests {
+		if _, err := decodeMnemonic(tt); !errors.Is(err, ErrInvalidMnemonic) {
+			t.Errorf("decodeMnemonic(%q): expected ErrInvalidMnemonic, but got %v", tt, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const cacheMagic = "genpass-wordlist-cache-v1 "
+
+func wordlistCachePath(source string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(source))
+	return filepath.Join(dir, NAME, "wordlists", hex.EncodeToString(sum[:])), nil
+}
+
+func readWordlistCache(path string, ttl time.Duration) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if time.Since(info.ModTime()) > ttl {
+		return nil, errors.New("expired")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	header, body, ok := bytes.Cut(data, []byte("\n"))
+	checksum, ok2 := bytes.CutPrefix(header, []byte(cacheMagic))
+	sum := sha256.Sum256(body)
+	if !ok || !ok2 || string(checksum) != hex.EncodeToString(sum[:]) {
+		return nil, errors.New("corrupted")
+	}
+	return body, nil
+}
+
+func writeWordlistCache(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	sum := sha256.Sum256(data)
+	fmt.Fprintf(f, "%v%x\n", cacheMagic, sum)
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (c *Command) readCommandOutputCached(command string) ([]byte, error) {
+	if c.WordlistCacheTTL == 0 || c.NoCache {
+		return readCommandOutput(command, c.Writer)
+	}
+	path, err := wordlistCachePath("exec:" + command)
+	if err != nil {
+		c.Warnf("wordlist cache: %v", err)
+		return readCommandOutput(command, c.Writer)
+	}
+	if !c.RefreshCache {
+		data, err := readWordlistCache(path, c.WordlistCacheTTL)
+		if err == nil {
+			c.Debugf("wordlist cache: using %v", path)
+			return data, nil
+		}
+		c.Debugf("wordlist cache: %v: %v", path, err)
+	}
+	data, err := readCommandOutput(command, c.Writer)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeWordlistCache(path, data); err != nil {
+		c.Warnf("wordlist cache: %v", err)
+	}
+	return data, nil
+}
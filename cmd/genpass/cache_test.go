@@ -0,0 +1,62 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReadCommandOutputCached(t *testing.T) {
+	requireCommands(t, "date")
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	const command = "date +%s%N"
+	c := &Command{Logger: Logger{Writer: io.Discard}, WordlistCacheTTL: time.Hour}
+	first, err := c.readCommandOutputCached(command)
+	if err != nil {
+		t.Fatalf("readCommandOutputCached: unexpected error: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	if got, err := c.readCommandOutputCached(command); err != nil {
+		t.Errorf("readCommandOutputCached: unexpected error: %v", err)
+	} else if string(got) != string(first) {
+		t.Errorf("readCommandOutputCached: expected the cached output %q, but got %q", first, got)
+	}
+
+	for _, opt := range []string{"--no-cache", "--refresh"} {
+		c := &Command{Logger: Logger{Writer: io.Discard}, WordlistCacheTTL: time.Hour}
+		if err := c.Option(opt, "", false); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := c.readCommandOutputCached(command); err != nil {
+			t.Errorf("readCommandOutputCached with %v: unexpected error: %v", opt, err)
+		} else if string(got) == string(first) {
+			t.Errorf("readCommandOutputCached with %v: expected a fresh output, but got the cached one", opt)
+		}
+	}
+
+	path, err := wordlistCachePath("exec:" + command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("garbage\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.readCommandOutputCached(command); err != nil {
+		t.Errorf("readCommandOutputCached with a corrupted cache: unexpected error: %v", err)
+	} else if string(got) == "garbage\n" || len(got) == 0 {
+		t.Errorf("readCommandOutputCached with a corrupted cache: got %q", got)
+	}
+	if _, err := readWordlistCache(path, time.Hour); err != nil {
+		t.Errorf("readWordlistCache: expected the cache to be rewritten, but got %v", err)
+	}
+	if _, err := readWordlistCache(path, time.Nanosecond); err == nil {
+		t.Errorf("readWordlistCache: expected an expired cache")
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"math/bits"
+	"strings"
+	"unicode"
+
+	"github.com/cions/genpass/internal/runeset"
+	"github.com/cions/go-colorterm"
+)
+
+var charClasses = []*unicode.RangeTable{
+	unicode.Ll,
+	unicode.Lu,
+	unicode.Nd,
+}
+
+const numCharClasses = 4
+
+func charClass(r rune) int {
+	for i, table := range charClasses {
+		if unicode.Is(table, r) {
+			return i
+		}
+	}
+	return len(charClasses)
+}
+
+func countCharClasses(s string) int {
+	var seen [numCharClasses]bool
+	var n int
+	for _, r := range s {
+		if i := charClass(r); !seen[i] {
+			seen[i] = true
+			n++
+		}
+	}
+	return n
+}
+
+func previewColor(r rune) colorterm.EscapeCode {
+	switch {
+	case unicode.IsLetter(r):
+		return colorterm.FgReset
+	case unicode.IsDigit(r):
+		return colorterm.FgBlue
+	case unicode.IsSpace(r):
+		return colorterm.FgReset
+	default:
+		return colorterm.FgMagenta
+	}
+}
+
+func colorizeClasses(s string) string {
+	if !colorterm.Enabled {
+		return s
+	}
+	var b strings.Builder
+	current := colorterm.FgReset
+	for _, r := range s {
+		if color := previewColor(r); color != current {
+			b.WriteString(color.String())
+			current = color
+		}
+		b.WriteRune(r)
+	}
+	if current != colorterm.FgReset {
+		b.WriteString(colorterm.FgReset.String())
+	}
+	return b.String()
+}
+
+func charClassSizes(set *runeset.RuneSet) [numCharClasses]int64 {
+	classes := set.SplitByClass()
+	size := func(name string) int64 {
+		class := classes[name]
+		return class.Picker().Size()
+	}
+	return [numCharClasses]int64{
+		size("lower"),
+		size("upper"),
+		size("digit"),
+		size("punct") + size("other"),
+	}
+}
+
+func positionClassSizes(positions []*runeset.RuneSet) [][numCharClasses]int64 {
+	cache := make(map[*runeset.RuneSet][numCharClasses]int64)
+	sizes := make([][numCharClasses]int64, len(positions))
+	for i, set := range positions {
+		size, ok := cache[set]
+		if !ok {
+			size = charClassSizes(set)
+			cache[set] = size
+		}
+		sizes[i] = size
+	}
+	return sizes
+}
+
+// sizes holds the class sizes of the set each position is drawn from.
+func minClassesProbability(sizes [][numCharClasses]int64, minClasses int) float64 {
+	// dist[s] is the probability that the characters so far cover exactly
+	// the classes in the bit set s.
+	var dist [1 << numCharClasses]float64
+	dist[0] = 1
+	for _, size := range sizes {
+		var total int64
+		for _, n := range size {
+			total += n
+		}
+		var next [1 << numCharClasses]float64
+		for s, q := range dist {
+			for i, n := range size {
+				next[s|1<<i] += q * float64(n) / float64(total)
+			}
+		}
+		dist = next
+	}
+
+	var p float64
+	for s, q := range dist {
+		if bits.OnesCount(uint(s)) >= minClasses {
+			p += q
+		}
+	}
+	return p
+}
+
+func countUniqueChars(s string) uint {
+	seen := make(map[rune]struct{})
+	for _, r := range s {
+		seen[r] = struct{}{}
+	}
+	return uint(len(seen))
+}
+
+func minUniqueProbability(size int64, nchars, minUnique uint) float64 {
+	dist := make([]float64, nchars+1)
+	dist[0] = 1
+	for i := range nchars {
+		for d := i + 1; d > 0; d-- {
+			dist[d] = dist[d]*float64(d)/float64(size) + dist[d-1]*float64(size-int64(d)+1)/float64(size)
+		}
+		dist[0] = 0
+	}
+
+	var p float64
+	for d := minUnique; d <= nchars; d++ {
+		p += dist[d]
+	}
+	return p
+}
+
+// Symbols are the characters that are neither letters nor digits, so letters
+// without case such as kana are not symbols.
+func countDigitsAndSymbols(s string) (digits, symbols uint) {
+	for _, r := range s {
+		switch {
+		case charClass(r) == 2:
+			digits++
+		case !unicode.IsLetter(r):
+			symbols++
+		}
+	}
+	return digits, symbols
+}
+
+func digitAndSymbolSizes(set *runeset.RuneSet) (digits, symbols int64) {
+	classes := set.SplitByClass()
+	letters := runeset.FromRangeTables(unicode.L)
+	digit, punct, other := classes["digit"], classes["punct"], classes["other"]
+	rest := other.Subtract(&letters)
+	return digit.Picker().Size(), punct.Picker().Size() + rest.Picker().Size()
+}
+
+// positions holds the set each position is drawn from.
+func minCountsProbability(positions []*runeset.RuneSet, minDigits, minSymbols uint) float64 {
+	type probs struct{ digit, symbol, other float64 }
+	cache := make(map[*runeset.RuneSet]probs)
+
+	// dist[d][s] is the probability of d digits and s symbols so far,
+	// where the counts saturate at minDigits and minSymbols.
+	dist := make([][]float64, minDigits+1)
+	for d := range dist {
+		dist[d] = make([]float64, minSymbols+1)
+	}
+	dist[0][0] = 1
+	for _, set := range positions {
+		q, ok := cache[set]
+		if !ok {
+			digits, symbols := digitAndSymbolSizes(set)
+			total := float64(set.Picker().Size())
+			q = probs{float64(digits) / total, float64(symbols) / total, 0}
+			q.other = 1 - q.digit - q.symbol
+			cache[set] = q
+		}
+		next := make([][]float64, minDigits+1)
+		for d := range next {
+			next[d] = make([]float64, minSymbols+1)
+		}
+		for d := range dist {
+			for s, p := range dist[d] {
+				next[d][s] += p * q.other
+				next[min(uint(d)+1, minDigits)][s] += p * q.digit
+				next[d][min(uint(s)+1, minSymbols)] += p * q.symbol
+			}
+		}
+		dist = next
+	}
+	return dist[minDigits][minSymbols]
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/cions/genpass/internal/runeset"
+	"github.com/cions/go-colorterm"
+)
+
+func TestCountCharClasses(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"abc", 1},
+		{"aBc", 2},
+		{"aB1", 3},
+		{"aB1!", 4},
+		{"ÀéあA", 3},
+	}
+
+	for _, tt := range tests {
+		if got := countCharClasses(tt.input); got != tt.want {
+			t.Errorf("countCharClasses(%q): expected %v, but got %v", tt.input, tt.want, got)
+		}
+	}
+}
+
+// enumeratePositions calls f with every string whose i-th character is
+// drawn from positions[i].
+func enumeratePositions(positions []*runeset.RuneSet, f func(s string)) {
+	var enumerate func(prefix []rune)
+	enumerate = func(prefix []rune) {
+		if len(prefix) == len(positions) {
+			f(string(prefix))
+			return
+		}
+		picker := positions[len(prefix)].Picker()
+		for i := range picker.Size() {
+			enumerate(append(prefix, picker.Get(i)))
+		}
+	}
+	enumerate(nil)
+}
+
+// testPositions returns the positions of nchars characters drawn from set,
+// with the first one drawn from first instead if it is not nil.
+func testPositions(set, first *runeset.RuneSet, nchars uint) []*runeset.RuneSet {
+	positions := slices.Repeat([]*runeset.RuneSet{set}, int(nchars))
+	if first != nil && nchars != 0 {
+		positions[0] = first
+	}
+	return positions
+}
+
+func TestMinClassesProbability(t *testing.T) {
+	set, err := runeset.Parse(`abAB1!`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sizes := charClassSizes(&set)
+	if sizes != [numCharClasses]int64{2, 2, 1, 1} {
+		t.Fatalf("charClassSizes: unexpected result %v", sizes)
+	}
+	first, err := runeset.Parse(`1`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, first := range []*runeset.RuneSet{nil, &first} {
+		for nchars := range uint(5) {
+			positions := testPositions(&set, first, nchars)
+			for minClasses := 1; minClasses <= numCharClasses; minClasses++ {
+				var accepted, total int
+				enumeratePositions(positions, func(s string) {
+					total++
+					if countCharClasses(s) >= minClasses {
+						accepted++
+					}
+				})
+
+				want := float64(accepted) / float64(total)
+				got := minClassesProbability(positionClassSizes(positions), minClasses)
+				if math.Abs(got-want) > 1e-9 {
+					t.Errorf("minClassesProbability(%v, %v, %v): expected %v, but got %v", first, nchars, minClasses, want, got)
+				}
+			}
+		}
+	}
+}
+
+func TestCountUniqueChars(t *testing.T) {
+	tests := []struct {
+		input string
+		want  uint
+	}{
+		{"", 0},
+		{"aaa", 1},
+		{"abca", 3},
+		{"aAあああ", 3},
+	}
+
+	for _, tt := range tests {
+		if got := countUniqueChars(tt.input); got != tt.want {
+			t.Errorf("countUniqueChars(%q): expected %v, but got %v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestMinUniqueProbability(t *testing.T) {
+	alphabet := []rune("abcd")
+	for nchars := range uint(6) {
+		for minUnique := uint(1); minUnique <= nchars; minUnique++ {
+			var accepted, total int
+			var enumerate func(prefix []rune)
+			enumerate = func(prefix []rune) {
+				if uint(len(prefix)) == nchars {
+					total++
+					if countUniqueChars(string(prefix)) >= minUnique {
+						accepted++
+					}
+					return
+				}
+				for _, r := range alphabet {
+					enumerate(append(prefix, r))
+				}
+			}
+			enumerate(nil)
+
+			want := float64(accepted) / float64(total)
+			got := minUniqueProbability(int64(len(alphabet)), nchars, minUnique)
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("minUniqueProbability(%v, %v): expected %v, but got %v", nchars, minUnique, want, got)
+			}
+		}
+	}
+}
+
+func TestDigitAndSymbolSizes(t *testing.T) {
+	set, err := runeset.Parse(`a-zA-Z0-9!-/ ぁ-ゖ０-９\u0300`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digits, symbols := digitAndSymbolSizes(&set)
+	if digits != 20 || symbols != 17 {
+		t.Errorf("digitAndSymbolSizes: expected (20, 17), but got (%v, %v)", digits, symbols)
+	}
+	if d, s := countDigitsAndSymbols("aあ1０! \u0300"); d != 2 || s != 3 {
+		t.Errorf("countDigitsAndSymbols: expected (2, 3), but got (%v, %v)", d, s)
+	}
+}
+
+func TestMinCountsProbability(t *testing.T) {
+	set, err := runeset.Parse(`a1!あ`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first, err := runeset.Parse(`!a`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, first := range []*runeset.RuneSet{nil, &first} {
+		for nchars := range uint(6) {
+			positions := testPositions(&set, first, nchars)
+			for minDigits := range uint(3) {
+				for minSymbols := range uint(3) {
+					var accepted, total int
+					enumeratePositions(positions, func(s string) {
+						total++
+						digits, symbols := countDigitsAndSymbols(s)
+						if digits >= minDigits && symbols >= minSymbols {
+							accepted++
+						}
+					})
+
+					want := float64(accepted) / float64(total)
+					got := minCountsProbability(positions, minDigits, minSymbols)
+					if math.Abs(got-want) > 1e-9 {
+						t.Errorf("minCountsProbability(%v, %v, %v, %v): expected %v, but got %v", first, nchars, minDigits, minSymbols, want, got)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestColorizeClasses(t *testing.T) {
+	saved := colorterm.Enabled
+	t.Cleanup(func() { colorterm.Enabled = saved })
+
+	colorterm.Enabled = false
+	if got := colorizeClasses("ab1!"); got != "ab1!" {
+		t.Errorf("colorizeClasses(%q) without color: expected %q, but got %q", "ab1!", "ab1!", got)
+	}
+
+	colorterm.Enabled = true
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"abc", "abc"},
+		{"ab12", "ab\x1b[34m12\x1b[39m"},
+		{"a!1b", "a\x1b[35m!\x1b[34m1\x1b[39mb"},
+		{"x y", "x y"},
+	}
+	for _, tt := range tests {
+		if got := colorizeClasses(tt.input); got != tt.want {
+			t.Errorf("colorizeClasses(%q): expected %q, but got %q", tt.input, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cions/go-options"
+)
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, NAME, "config.toml")
+}
+
+func findConfigOption(args []string) (string, bool) {
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return "", false
+		case arg == "--config" && i+1 < len(args):
+			return args[i+1], true
+		case strings.HasPrefix(arg, "--config="):
+			return strings.TrimPrefix(arg, "--config="), true
+		}
+	}
+	return "", false
+}
+
+func parseConfigValue(s string) (string, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		return strconv.Unquote(s)
+	case strings.HasPrefix(s, `'`):
+		if len(s) < 2 || !strings.HasSuffix(s, `'`) || strings.Contains(s[1:len(s)-1], `'`) {
+			return "", strconv.ErrSyntax
+		}
+		return s[1 : len(s)-1], nil
+	default:
+		return s, nil
+	}
+}
+
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case quote == 0 && ch == '#':
+			return line[:i]
+		case quote == 0 && (ch == '"' || ch == '\''):
+			quote = ch
+		case quote == '"' && ch == '\\':
+			i++
+		case ch == quote:
+			quote = 0
+		}
+	}
+	return line
+}
+
+func (c *Command) configOption(key, value string) error {
+	switch key {
+	case "variant":
+		switch value {
+		case "passphrase":
+			c.Variant = Passphrase
+			return nil
+		case "password":
+			return c.Option("--password", "", false)
+		case "hex":
+			return c.Option("--hex", "", false)
+		case "base64":
+			return c.Option("--base64", "", false)
+		default:
+			return errors.New("possible values are 'passphrase', 'password', 'hex', 'base64'")
+		}
+	case "wordlist":
+		// Unlike -w, a default wordlist does not select the variant.
+		c.Wordlist = value
+		return nil
+	case "config", "help", "version":
+		return options.ErrUnknown
+	case "master", "pepper", "test-seed", "i-know-this-is-insecure":
+		return errors.New("can be given only on the command line")
+	}
+
+	name := "--" + key
+	switch c.Kind(name) {
+	case options.Boolean:
+		switch value {
+		case "true":
+			return c.Option(name, "", false)
+		case "false":
+			return nil
+		default:
+			return errors.New("must be true or false")
+		}
+	case options.Required:
+		return c.Option(name, value, true)
+	default:
+		return options.ErrUnknown
+	}
+}
+
+func (c *Command) loadConfig(path string, explicit bool) error {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return options.Errorf("%s:%d: expected KEY = VALUE", path, lineno)
+		}
+		key = strings.TrimSpace(key)
+		value, err := parseConfigValue(strings.TrimSpace(value))
+		if err != nil {
+			return options.Errorf("%s:%d: invalid value for %s", path, lineno, key)
+		}
+		if err := c.configOption(key, value); errors.Is(err, options.ErrUnknown) {
+			return options.Errorf("%s:%d: unknown key %s", path, lineno, key)
+		} else if err != nil {
+			return options.Errorf("%s:%d: %s: %w", path, lineno, key, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := writeTempFile(t, `# genpass configuration
+variant = "password"
+bits = 100  # at least 100 bits
+show-bits = true
+separator = "#"
+first-char-class = '\L'
+`)
+
+	c := &Command{}
+	if err := c.loadConfig(path, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c.Variant != Password || c.Charset == nil {
+		t.Errorf("variant: expected password")
+	}
+	if c.Bits != 100 {
+		t.Errorf("bits: expected 100, but got %v", c.Bits)
+	}
+	if !c.ShowBits {
+		t.Errorf("show-bits: expected true")
+	}
+	if c.Separator != "#" {
+		t.Errorf("separator: expected %q, but got %q", "#", c.Separator)
+	}
+	if c.FirstChars == nil || c.FirstChars.String() != "A-Z" {
+		t.Errorf("first-char-class: expected A-Z")
+	}
+}
+
+func TestLoadConfig_errors(t *testing.T) {
+	tests := []string{
+		"bits",
+		"bits = x",
+		"unknown = 1",
+		"help = true",
+		"show-bits = yes",
+		"variant = \"other\"",
+		"separator = \"unterminated",
+		"[section]",
+		"master = hunter2",
+		"pepper = hunter2",
+		"test-seed = 42",
+		"i-know-this-is-insecure = true",
+	}
+
+	for _, tt := range tests {
+		c := &Command{}
+		if err := c.loadConfig(writeTempFile(t, tt), true); err == nil {
+			t.Errorf("loadConfig(%q): expected a non-nil error", tt)
+		}
+	}
+
+	c := &Command{}
+	if err := c.loadConfig(writeTempFile(t, "")+".missing", false); err != nil {
+		t.Errorf("loadConfig(missing, false): unexpected error: %v", err)
+	}
+}
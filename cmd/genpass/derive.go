@@ -0,0 +1,20 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	deriveTime       = 3
+	deriveMemory     = 64 * 1024
+	deriveThreads    = 4
+	deriveKeyLen     = 32
+	deriveSaltPrefix = "genpass-derive-v1:"
+)
+
+func deriveKey(master, site string) []byte {
+	return argon2.IDKey([]byte(master), []byte(deriveSaltPrefix+site), deriveTime, deriveMemory, deriveThreads, deriveKeyLen)
+}
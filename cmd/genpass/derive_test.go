@@ -0,0 +1,47 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	key := deriveKey("hunter2", "example.com")
+	want := "07ac4545bf0d467b22412acd33d22cd8625d9fbc9fe97d4fac5b3ebdc2f15cea"
+	if got := hex.EncodeToString(key); got != want {
+		t.Errorf("expected %v, but got %v", want, got)
+	}
+
+	other := deriveKey("hunter2", "example.org")
+	if bytes.Equal(key, other) {
+		t.Errorf("different sites derived the same key")
+	}
+}
+
+func TestKeystreamReader(t *testing.T) {
+	key, _ := hex.DecodeString("1b008a6633d64fd9b289d499dde8f412e14d99fe1af59405eccd0bdd87896c65")
+
+	want := make([]byte, 100)
+	io.ReadFull(newKeystreamReader(key), want)
+	if got, first := hex.EncodeToString(want[:32]), "0831276709287693f3edaf702f0c97cffa9a29ab033c9f7a0d2d612a44ca10fb"; got != first {
+		t.Errorf("expected %v, but got %v", first, got)
+	}
+
+	for _, chunk := range []int{1, 7, 32, 33} {
+		ks := newKeystreamReader(key)
+		var got []byte
+		for len(got) < len(want) {
+			buf := make([]byte, min(chunk, len(want)-len(got)))
+			io.ReadFull(ks, buf)
+			got = append(got, buf...)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("reading in chunks of %v changed the keystream", chunk)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestEmojiTable(t *testing.T) {
+	excluded := []rune{
+		0x200D,  // ZERO WIDTH JOINER
+		0xFE0F,  // VARIATION SELECTOR-16
+		0x1F1E6, // REGIONAL INDICATOR SYMBOL LETTER A
+		0x1F3F4, // WAVING BLACK FLAG
+		0x1F3FB, // EMOJI MODIFIER FITZPATRICK TYPE-1-2
+		0x1F3FF, // EMOJI MODIFIER FITZPATRICK TYPE-6
+		0x1F9B0, // EMOJI COMPONENT RED HAIR
+		0xE007F, // CANCEL TAG
+	}
+	for _, r := range excluded {
+		if unicode.Is(emojiTable, r) {
+			t.Errorf("emojiTable contains U+%04X", r)
+		}
+	}
+
+	c := &Command{Count: 1}
+	if err := c.Option("--emoji", "", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Length = 64
+	generator, _, err := c.getGenerator()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range generator() {
+		if !unicode.Is(emojiTable, r) {
+			t.Errorf("generated a non-emoji character U+%04X", r)
+		}
+	}
+}
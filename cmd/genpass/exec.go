@@ -0,0 +1,55 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var (
+	wordlistCommandTimeout   = 30 * time.Second
+	maxWordlistCommandOutput = 64 << 20
+)
+
+func readCommandOutput(command string, stderr io.Writer) ([]byte, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("exec: empty command")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), wordlistCommandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(stdout, int64(maxWordlistCommandOutput)+1))
+	if err == nil && len(data) > maxWordlistCommandOutput {
+		err = fmt.Errorf("exec: %v: output exceeds %d bytes", args[0], maxWordlistCommandOutput)
+	}
+	if err != nil {
+		cancel()
+		cmd.Wait()
+		return nil, err
+	}
+	if err := cmd.Wait(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("exec: %v: timed out after %v", args[0], wordlistCommandTimeout)
+		}
+		return nil, fmt.Errorf("exec: %v: %w", args[0], err)
+	}
+	return data, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func requireCommands(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		if _, err := exec.LookPath(name); err != nil {
+			t.Skipf("%v is not available", name)
+		}
+	}
+}
+
+func TestGetWordlist_exec(t *testing.T) {
+	requireCommands(t, "printf")
+
+	var c Command
+	words, _, err := c.getWordlist(`exec:printf foo\nbar\n\nbaz\n`)
+	if err != nil {
+		t.Fatalf("getWordlist: unexpected error: %v", err)
+	}
+	if want := []string{"foo", "bar", "baz"}; !slices.Equal(words, want) {
+		t.Errorf("getWordlist: expected %q, but got %q", want, words)
+	}
+}
+
+func TestRun_execStderr(t *testing.T) {
+	requireCommands(t, "ls")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var stderr bytes.Buffer
+	args := []string{"-w", "exec:ls /genpass-no-such-file"}
+	if err := run(args, nil, io.Discard, &stderr); err == nil {
+		t.Errorf("run(%q): expected an error", args)
+	}
+	if !strings.Contains(stderr.String(), "genpass-no-such-file") {
+		t.Errorf("run(%q): expected the standard error of the command, but got %q", args, stderr.String())
+	}
+}
+
+func TestReadCommandOutput_errors(t *testing.T) {
+	requireCommands(t, "false", "sleep", "seq")
+
+	savedTimeout, savedMax := wordlistCommandTimeout, maxWordlistCommandOutput
+	t.Cleanup(func() {
+		wordlistCommandTimeout, maxWordlistCommandOutput = savedTimeout, savedMax
+	})
+	wordlistCommandTimeout, maxWordlistCommandOutput = 200*time.Millisecond, 100
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"", "empty command"},
+		{"false", "exit status 1"},
+		{"sleep 5", "timed out"},
+		{"seq 1 1000", "exceeds 100 bytes"},
+		{"genpass-no-such-command", "not found"},
+	}
+	for _, tt := range tests {
+		_, err := readCommandOutput(tt.command, io.Discard)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("readCommandOutput(%q): expected an error containing %q, but got %v", tt.command, tt.want, err)
+		}
+	}
+}
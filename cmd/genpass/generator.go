@@ -4,77 +4,516 @@
 package main
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	mathrand "math/rand/v2"
+	"os"
+	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cions/genpass/internal/runeset"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
 )
 
 type Generator func() string
 
-func choice[S ~[]E, E any](slice S) E {
-	n := big.NewInt(int64(len(slice)))
-	i, err := rand.Int(rand.Reader, n)
+var (
+	ErrRandomSource = errors.New("random source failure")
+	ErrConstraints  = errors.New("could not satisfy constraints")
+)
+
+type countingReader struct {
+	r   io.Reader
+	n   int64
+	err error
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	if err != nil && cr.err == nil {
+		cr.err = err
+	}
+	return n, err
+}
+
+var random io.Reader = rand.Reader
+
+type entropyFile struct {
+	*os.File
+}
+
+func (ef entropyFile) Read(p []byte) (int, error) {
+	n, err := ef.File.Read(p)
+	if err == io.EOF {
+		err = fmt.Errorf("%v: %w", ef.Name(), io.ErrUnexpectedEOF)
+	}
+	return n, err
+}
+
+func newSeededReader(seed uint64) io.Reader {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], seed)
+	return mathrand.NewChaCha8(key)
+}
+
+func randomSourceName() string {
+	if random == rand.Reader {
+		return "crypto/rand"
+	}
+	return fmt.Sprintf("%T (not crypto/rand)", random)
+}
+
+func randomInt64(n int64) int64 {
+	i, err := rand.Int(random, big.NewInt(n))
 	if err != nil {
 		panic(fmt.Sprintf("crypto/rand: %v", err))
 	} else if !i.IsInt64() {
 		panic("crypto/rand: out of range")
 	}
-	return slice[i.Int64()]
+	return i.Int64()
+}
+
+func randomInt(n int) int {
+	return int(randomInt64(int64(n)))
+}
+
+func choice[S ~[]E, E any](slice S) E {
+	return slice[randomInt(len(slice))]
+}
+
+func weightedChoice[S ~[]E, E any](slice S, cumWeights []uint64) E {
+	n := new(big.Int).SetUint64(cumWeights[len(cumWeights)-1])
+	i, err := rand.Int(random, n)
+	if err != nil {
+		panic(fmt.Sprintf("crypto/rand: %v", err))
+	} else if !i.IsUint64() {
+		panic("crypto/rand: out of range")
+	}
+	idx, _ := slices.BinarySearch(cumWeights, i.Uint64()+1)
+	return slice[idx]
+}
+
+func insertDigits(words []string, ndigits uint) []string {
+	const digits = "0123456789"
+	result := make([]string, 0, uint(len(words))+ndigits)
+	remaining := int(ndigits)
+	for len(words) != 0 || remaining != 0 {
+		if randomInt(len(words)+remaining) < remaining {
+			i := randomInt(len(digits))
+			result = append(result, digits[i:i+1])
+			remaining--
+		} else {
+			result = append(result, words[0])
+			words = words[1:]
+		}
+	}
+	return result
+}
+
+type Wordlist struct {
+	Words      []string
+	Weights    []uint64
+	cumWeights []uint64
 }
 
-func newPassphraseGenerator(wordlist []string, nwords uint) Generator {
-	if len(wordlist) == 0 {
-		panic("newPassphraseGenerator: empty wordlist")
+func newWordlist(words []string, weights []uint64) *Wordlist {
+	if len(words) == 0 {
+		panic("newWordlist: empty wordlist")
 	}
+	if weights == nil {
+		return &Wordlist{Words: words}
+	} else if len(words) != len(weights) {
+		panic("newWordlist: length mismatch")
+	}
+	var total uint64
+	cumWeights := make([]uint64, len(weights))
+	for i, weight := range weights {
+		if total+weight < total {
+			panic("newWordlist: total weight overflows")
+		}
+		total += weight
+		cumWeights[i] = total
+	}
+	if total == 0 {
+		panic("newWordlist: total weight must not be zero")
+	}
+	return &Wordlist{Words: words, Weights: weights, cumWeights: cumWeights}
+}
+
+func (wl *Wordlist) Random() string {
+	if wl.cumWeights == nil {
+		return choice(wl.Words)
+	}
+	return weightedChoice(wl.Words, wl.cumWeights)
+}
+
+type Case int
+
+const (
+	CaseLower Case = iota
+	CaseUpper
+	CaseMixed
+)
+
+func randomizeCase(s string, tag language.Tag) string {
+	buf := make([]byte, (utf8.RuneCountInString(s)+7)/8)
+	if _, err := io.ReadFull(random, buf); err != nil {
+		panic(fmt.Sprintf("crypto/rand: %v", err))
+	}
+	var upper, lower cases.Caser
+	if tag != language.Und {
+		upper, lower = cases.Upper(tag), cases.Lower(tag)
+	}
+	var b strings.Builder
+	var i int
+	for _, r := range s {
+		upperCase := buf[i/8]&(1<<(i%8)) != 0
+		switch {
+		case tag != language.Und && upperCase:
+			b.WriteString(upper.String(string(r)))
+		case tag != language.Und:
+			b.WriteString(lower.String(string(r)))
+		case upperCase:
+			b.WriteRune(unicode.ToUpper(r))
+		default:
+			b.WriteRune(unicode.ToLower(r))
+		}
+		i++
+	}
+	return b.String()
+}
+
+// mixedCaseBounds returns the largest size in bytes and the smallest and
+// largest length in runes of randomizeCase(s, tag).
+func mixedCaseBounds(s string, tag language.Tag) (maxBytes, minRunes, maxRunes uint) {
+	for _, r := range s {
+		upper := CaseUpper.apply(string(r), tag)
+		lower := CaseLower.apply(string(r), tag)
+		nupper, nlower := uint(utf8.RuneCountInString(upper)), uint(utf8.RuneCountInString(lower))
+		maxBytes += uint(max(len(upper), len(lower)))
+		minRunes += min(nupper, nlower)
+		maxRunes += max(nupper, nlower)
+	}
+	return maxBytes, minRunes, maxRunes
+}
+
+func (wcase Case) apply(word string, tag language.Tag) string {
+	switch wcase {
+	case CaseLower:
+		if tag != language.Und {
+			return cases.Lower(tag).String(word)
+		}
+		return strings.ToLower(word)
+	case CaseUpper:
+		if tag != language.Und {
+			return cases.Upper(tag).String(word)
+		}
+		return strings.ToUpper(word)
+	case CaseMixed:
+		return randomizeCase(word, tag)
+	default:
+		panic("genpass: invalid Case")
+	}
+}
+
+func newPassphraseWordsGenerator(wordlists []*Wordlist, nwords, maxChars uint, sep string, wcase Case, tag language.Tag) func() []string {
+	if len(wordlists) == 0 {
+		panic("newPassphraseWordsGenerator: no wordlists")
+	}
+	sepLen := uint(utf8.RuneCountInString(sep))
+	return func() []string {
+		if maxChars == 0 {
+			words := make([]string, nwords)
+			for i := range nwords {
+				words[i] = wcase.apply(wordlists[i%uint(len(wordlists))].Random(), tag)
+			}
+			return words
+		}
+		var words []string
+		var length uint
+		for nwords == 0 || uint(len(words)) < nwords {
+			word := wcase.apply(wordlists[len(words)%len(wordlists)].Random(), tag)
+			n := uint(utf8.RuneCountInString(word))
+			if len(words) != 0 {
+				n += sepLen
+			}
+			if length+n > maxChars {
+				if len(words) == 0 {
+					continue
+				}
+				break
+			}
+			words = append(words, word)
+			length += n
+		}
+		return words
+	}
+}
+
+func joinPassphrase(words []string, ndigits uint, sep string) string {
+	if ndigits != 0 {
+		words = insertDigits(words, ndigits)
+	}
+	return strings.Join(words, sep)
+}
+
+func newPassphraseGenerator(wordlists []*Wordlist, nwords, ndigits, maxChars uint, sep string, wcase Case, tag language.Tag) Generator {
+	words := newPassphraseWordsGenerator(wordlists, nwords, maxChars, sep, wcase, tag)
 	return func() string {
-		words := make([]string, nwords)
-		for i := range nwords {
-			words[i] = choice(wordlist)
+		return joinPassphrase(words(), ndigits, sep)
+	}
+}
+
+func acrostic(words []string) string {
+	var b strings.Builder
+	for _, word := range words {
+		if r, size := utf8.DecodeRuneInString(word); size != 0 {
+			b.WriteRune(r)
 		}
-		return strings.Join(words, " ")
 	}
+	return b.String()
 }
 
-func newPasswordGenerator(picker *runeset.Picker, nchars uint) Generator {
-	if picker.Size() == 0 {
+func newPasswordGenerator(picker *runeset.Picker, nchars uint, first, last *runeset.Picker) Generator {
+	if picker.Size() == 0 || first.Size() == 0 || last.Size() == 0 {
 		panic("newPasswordGenerator: empty runeset")
 	}
 	return func() string {
-		chars := make([]string, nchars)
-		for i := range nchars {
-			chars[i] = string(picker.Random())
+		switch nchars {
+		case 0:
+			return ""
+		case 1:
+			return first.RandomStringFrom(random, 1)
+		default:
+			return first.RandomStringFrom(random, 1) +
+				picker.RandomStringFrom(random, int(nchars-2)) +
+				last.RandomStringFrom(random, 1)
+		}
+	}
+}
+
+func newShuffleGenerator(runes []rune, nchars uint) Generator {
+	if nchars > uint(len(runes)) {
+		panic("newShuffleGenerator: nchars exceeds the number of runes")
+	}
+	return func() string {
+		s := slices.Clone(runes)
+		for i := range int(nchars) {
+			j := i + randomInt(len(s)-i)
+			s[i], s[j] = s[j], s[i]
 		}
-		return strings.Join(chars, "")
+		return string(s[:nchars])
 	}
 }
 
-func newHexGenerator(nchars uint) Generator {
+type keystreamReader struct {
+	key     []byte
+	counter uint64
+	buf     []byte
+}
+
+func newKeystreamReader(key []byte) *keystreamReader {
+	return &keystreamReader{key: key}
+}
+
+func (ks *keystreamReader) Read(p []byte) (int, error) {
+	var n int
+	for n < len(p) {
+		if len(ks.buf) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], ks.counter)
+			ks.counter++
+			mac := hmac.New(sha256.New, ks.key)
+			mac.Write(counter[:])
+			ks.buf = mac.Sum(nil)
+		}
+		m := copy(p[n:], ks.buf)
+		ks.buf = ks.buf[m:]
+		n += m
+	}
+	return n, nil
+}
+
+func applyPepper(buf, pepper []byte) {
+	stream := make([]byte, len(buf))
+	io.ReadFull(newKeystreamReader(pepper), stream)
+	subtle.XORBytes(buf, buf, stream)
+}
+
+func newHexGenerator(nchars uint, pepper []byte) Generator {
 	if nchars == 0 {
 		panic("newHexGenerator: nchars must not be zero")
 	}
 	return func() string {
 		buf := make([]byte, (nchars-1)/2+1)
-		if _, err := rand.Read(buf); err != nil {
+		if _, err := io.ReadFull(random, buf); err != nil {
 			panic(fmt.Sprintf("crypto/rand: %v", err))
 		}
+		if pepper != nil {
+			applyPepper(buf, pepper)
+		}
 		return hex.EncodeToString(buf)[:nchars]
 	}
 }
 
-func newBase64Generator(nchars uint) Generator {
+func newBase64Generator(nchars uint, pepper []byte) Generator {
 	if nchars == 0 {
 		panic("newBase64Generator: nchars must not be zero")
 	}
 	return func() string {
 		buf := make([]byte, 3*((nchars-1)/4+1))
-		if _, err := rand.Read(buf); err != nil {
+		if _, err := io.ReadFull(random, buf); err != nil {
 			panic(fmt.Sprintf("crypto/rand: %v", err))
 		}
+		if pepper != nil {
+			applyPepper(buf, pepper)
+		}
 		return base64.URLEncoding.EncodeToString(buf)[:nchars]
 	}
 }
+
+const (
+	defaultRetryLimit = 10000
+	minAcceptance     = 0.01
+)
+
+func newFilterGenerator(generator Generator, accept func(string) bool, retryLimit uint) Generator {
+	if retryLimit == 0 {
+		panic("newFilterGenerator: retryLimit must not be zero")
+	}
+	return func() string {
+		for range retryLimit {
+			if s := generator(); accept(s) {
+				return s
+			}
+		}
+		panic(fmt.Errorf("%w (retry limit: %d)", ErrConstraints, retryLimit))
+	}
+}
+
+func newAppendGenerator(generator Generator, picker *runeset.Picker, n uint) Generator {
+	if picker.Size() == 0 {
+		panic("newAppendGenerator: empty runeset")
+	}
+	return func() string {
+		return generator() + picker.RandomStringFrom(random, int(n))
+	}
+}
+
+func newInsertGenerator(generator Generator, pos uint, picker *runeset.Picker) Generator {
+	if pos == 0 || picker.Size() == 0 {
+		panic("newInsertGenerator: invalid position or empty runeset")
+	}
+	return func() string {
+		runes := []rune(generator())
+		if pos <= uint(len(runes)) {
+			runes[pos-1] = []rune(picker.RandomStringFrom(random, 1))[0]
+		}
+		return string(runes)
+	}
+}
+
+func newUniqueGenerator(generator Generator, retryLimit uint) Generator {
+	seen := make(map[string]bool)
+	return newFilterGenerator(generator, func(s string) bool {
+		if seen[s] {
+			return false
+		}
+		seen[s] = true
+		return true
+	}, retryLimit)
+}
+
+func newMaxBytesGenerator(generator Generator, maxBytes uint, sep string) Generator {
+	if maxBytes == 0 {
+		panic("newMaxBytesGenerator: maxBytes must not be zero")
+	}
+	return func() string {
+		s := generator()
+		for uint(len(s)) > maxBytes {
+			if sep == "" {
+				_, size := utf8.DecodeLastRuneInString(s)
+				s = s[:len(s)-size]
+			} else if i := strings.LastIndex(s, sep); i >= 0 {
+				s = s[:i]
+			} else {
+				s = ""
+			}
+		}
+		return s
+	}
+}
+
+const (
+	hexAlphabet    = "0123456789abcdef"
+	base64Alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// newSplitGenerator labels the shares "share K/N", prefixed with "secret M "
+// if numbered is true so that the shares of different strings can be told
+// apart.
+func newSplitGenerator(generator Generator, alphabet string, nshares uint, numbered bool) Generator {
+	if nshares < 2 {
+		panic("newSplitGenerator: nshares must be at least 2")
+	}
+	var nsecrets uint
+	return func() string {
+		secret := generator()
+		last := make([]byte, len(secret))
+		for i := range len(secret) {
+			last[i] = byte(strings.IndexByte(alphabet, secret[i]))
+		}
+
+		nsecrets++
+		var prefix string
+		if numbered {
+			prefix = fmt.Sprintf("secret %d ", nsecrets)
+		}
+		var b strings.Builder
+		for n := range nshares - 1 {
+			fmt.Fprintf(&b, "%vshare %d/%d\t", prefix, n+1, nshares)
+			for i := range last {
+				v := byte(randomInt64(int64(len(alphabet))))
+				last[i] ^= v
+				b.WriteByte(alphabet[v])
+			}
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%vshare %d/%d\t", prefix, nshares, nshares)
+		for _, v := range last {
+			b.WriteByte(alphabet[v])
+		}
+		return b.String()
+	}
+}
+
+func newHyphenateGenerator(generator Generator, every uint, delim string) Generator {
+	if every == 0 {
+		panic("newHyphenateGenerator: every must not be zero")
+	}
+	return func() string {
+		var b strings.Builder
+		var n uint
+		for _, r := range generator() {
+			if n != 0 && n%every == 0 {
+				b.WriteString(delim)
+			}
+			b.WriteRune(r)
+			n++
+		}
+		return b.String()
+	}
+}
@@ -0,0 +1,419 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/cions/genpass/internal/runeset"
+	"golang.org/x/text/language"
+)
+
+func constGenerator(s string) Generator {
+	return func() string {
+		return s
+	}
+}
+
+func TestMaxBytesGenerator(t *testing.T) {
+	tests := []struct {
+		input    string
+		maxBytes uint
+		sep      string
+		want     string
+	}{
+		{"abcdef", 6, "", "abcdef"},
+		{"abcdef", 4, "", "abcd"},
+		{"あいう", 9, "", "あいう"},
+		{"あいう", 8, "", "あい"},
+		{"あいう", 6, "", "あい"},
+		{"あいう", 5, "", "あ"},
+		{"aあbい", 7, "", "aあb"},
+		{"aあbい", 6, "", "aあb"},
+		{"aあbい", 4, "", "aあ"},
+		{"aあbい", 3, "", "a"},
+		{"foo bar baz", 11, " ", "foo bar baz"},
+		{"foo bar baz", 10, " ", "foo bar"},
+		{"foo bar baz", 7, " ", "foo bar"},
+		{"foo bar baz", 6, " ", "foo"},
+		{"ねこ いぬ", 13, " ", "ねこ いぬ"},
+		{"ねこ いぬ", 12, " ", "ねこ"},
+	}
+
+	for _, tt := range tests {
+		generator := newMaxBytesGenerator(constGenerator(tt.input), tt.maxBytes, tt.sep)
+		if got := generator(); got != tt.want {
+			t.Errorf("newMaxBytesGenerator(%q, %v, %q): expected %q, but got %q", tt.input, tt.maxBytes, tt.sep, tt.want, got)
+		}
+	}
+}
+
+func TestRandomSourceName(t *testing.T) {
+	if got := randomSourceName(); got != "crypto/rand" {
+		t.Errorf("expected crypto/rand, but got %v", got)
+	}
+
+	saved := random
+	t.Cleanup(func() { random = saved })
+	random = bytes.NewReader(nil)
+	if got := randomSourceName(); !strings.Contains(got, "not crypto/rand") {
+		t.Errorf("expected a non-crypto/rand source, but got %v", got)
+	}
+}
+
+func TestFilterGenerator(t *testing.T) {
+	var n int
+	generator := newFilterGenerator(func() string {
+		n++
+		return strings.Repeat("a", n)
+	}, func(s string) bool {
+		return len(s) == 3
+	}, 5)
+	if got := generator(); got != "aaa" {
+		t.Errorf("expected %q, but got %q", "aaa", got)
+	}
+
+	n = 0
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrConstraints) {
+			t.Errorf("expected ErrConstraints, but got %v", err)
+		}
+		if n != 5 {
+			t.Errorf("expected 5 attempts, but got %v", n)
+		}
+	}()
+	generator = newFilterGenerator(func() string {
+		n++
+		return "a"
+	}, func(string) bool {
+		return false
+	}, 5)
+	generator()
+	t.Errorf("expected a panic")
+}
+
+func TestWeightedChoice(t *testing.T) {
+	words := []string{"a", "b", "c", "d"}
+	cumWeights := []uint64{0, 1, 1, 3}
+
+	for range 100 {
+		if got := weightedChoice(words, cumWeights); got != "b" && got != "d" {
+			t.Errorf("weightedChoice() returned a zero-weight element %q", got)
+		}
+	}
+}
+
+func TestPassphraseGenerator_digits(t *testing.T) {
+	wordlist := newWordlist([]string{"foo", "bar", "baz"}, nil)
+
+	for ndigits := range uint(5) {
+		generator := newPassphraseGenerator([]*Wordlist{wordlist}, 4, ndigits, 0, " ", CaseLower, language.Und)
+		for range 20 {
+			passphrase := generator()
+			var nwords, ndigitsGot uint
+			for _, token := range strings.Split(passphrase, " ") {
+				switch {
+				case len(token) == 1 && token[0] >= '0' && token[0] <= '9':
+					ndigitsGot++
+				case strings.Contains("foo bar baz", token):
+					nwords++
+				default:
+					t.Errorf("unexpected token %q in %q", token, passphrase)
+				}
+			}
+			if nwords != 4 || ndigitsGot != ndigits {
+				t.Errorf("expected 4 words and %v digits, but got %q", ndigits, passphrase)
+			}
+		}
+	}
+}
+
+func TestPassphraseGenerator_maxChars(t *testing.T) {
+	wordlist := newWordlist([]string{"a", "bcd", "efghijk"}, nil)
+
+	for _, maxChars := range []uint{1, 5, 7, 12, 30} {
+		generator := newPassphraseGenerator([]*Wordlist{wordlist}, 0, 0, maxChars, " ", CaseLower, language.Und)
+		for range 20 {
+			passphrase := generator()
+			if passphrase == "" || uint(utf8.RuneCountInString(passphrase)) > maxChars {
+				t.Errorf("maxChars=%v: unexpected passphrase %q", maxChars, passphrase)
+			}
+		}
+	}
+}
+
+func TestHyphenateGenerator(t *testing.T) {
+	tests := []struct {
+		input string
+		every uint
+		delim string
+		want  string
+	}{
+		{"", 5, "-", ""},
+		{"abcde", 5, "-", "abcde"},
+		{"abcdefghij", 5, "-", "abcde-fghij"},
+		{"abcdefghijkl", 5, "-", "abcde-fghij-kl"},
+		{"abcdefg", 2, " ", "ab cd ef g"},
+		{"あいうえお", 2, "--", "あい--うえ--お"},
+	}
+
+	for _, tt := range tests {
+		generator := newHyphenateGenerator(constGenerator(tt.input), tt.every, tt.delim)
+		if got := generator(); got != tt.want {
+			t.Errorf("newHyphenateGenerator(%q, %v, %q): expected %q, but got %q", tt.input, tt.every, tt.delim, tt.want, got)
+		}
+	}
+}
+
+func TestAppendGenerator(t *testing.T) {
+	saved := random
+	t.Cleanup(func() { random = saved })
+	random = bytes.NewReader(make([]byte, 1024))
+
+	set, err := runeset.Parse("#$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	generator := newAppendGenerator(constGenerator("foo bar"), set.Picker(), 3)
+	if got, want := generator(), "foo bar###"; got != want {
+		t.Errorf("newAppendGenerator: expected %q, but got %q", want, got)
+	}
+}
+
+func TestSplitGenerator(t *testing.T) {
+	for _, alphabet := range []string{hexAlphabet, base64Alphabet} {
+		secret := alphabet[:8] + alphabet[len(alphabet)-8:]
+		lines := strings.Split(newSplitGenerator(constGenerator(secret), alphabet, 3, false)(), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("newSplitGenerator: expected 3 lines, but got %q", lines)
+		}
+
+		combined := make([]byte, len(secret))
+		for i, line := range lines {
+			label, share, _ := strings.Cut(line, "\t")
+			if want := fmt.Sprintf("share %d/3", i+1); label != want {
+				t.Errorf("newSplitGenerator: expected label %q, but got %q", want, label)
+			}
+			for j := range len(share) {
+				combined[j] ^= byte(strings.IndexByte(alphabet, share[j]))
+			}
+		}
+		for j := range combined {
+			combined[j] = alphabet[combined[j]]
+		}
+		if got := string(combined); got != secret {
+			t.Errorf("newSplitGenerator: the shares combine to %q instead of %q", got, secret)
+		}
+	}
+
+	generator := newSplitGenerator(constGenerator("0f"), hexAlphabet, 2, true)
+	for m := 1; m <= 2; m++ {
+		for i, line := range strings.Split(generator(), "\n") {
+			label, _, _ := strings.Cut(line, "\t")
+			if want := fmt.Sprintf("secret %d share %d/2", m, i+1); label != want {
+				t.Errorf("newSplitGenerator: expected label %q, but got %q", want, label)
+			}
+		}
+	}
+}
+
+func TestInsertGenerator(t *testing.T) {
+	set, err := runeset.Parse("#")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		input string
+		pos   uint
+		want  string
+	}{
+		{"abcde", 1, "#bcde"},
+		{"abcde", 4, "abc#e"},
+		{"abcde", 5, "abcd#"},
+		{"abcde", 6, "abcde"},
+		{"あいう", 2, "あ#う"},
+	}
+
+	for _, tt := range tests {
+		generator := newInsertGenerator(constGenerator(tt.input), tt.pos, set.Picker())
+		if got := generator(); got != tt.want {
+			t.Errorf("newInsertGenerator(%q, %v): expected %q, but got %q", tt.input, tt.pos, tt.want, got)
+		}
+	}
+}
+
+func TestPassphraseGenerator_case(t *testing.T) {
+	wordlist := newWordlist([]string{"Foo", "bar", "BAZ"}, nil)
+
+	tests := []struct {
+		wcase Case
+		check func(string) bool
+	}{
+		{CaseLower, func(s string) bool { return s == strings.ToLower(s) }},
+		{CaseUpper, func(s string) bool { return s == strings.ToUpper(s) }},
+		{CaseMixed, func(s string) bool { return true }},
+	}
+	for _, tt := range tests {
+		generator := newPassphraseGenerator([]*Wordlist{wordlist}, 4, 0, 0, " ", tt.wcase, language.Und)
+		for range 20 {
+			passphrase := generator()
+			for _, word := range strings.Split(passphrase, " ") {
+				if !slices.Contains([]string{"foo", "bar", "baz"}, strings.ToLower(word)) || !tt.check(word) {
+					t.Errorf("Case(%v): unexpected word %q in %q", tt.wcase, word, passphrase)
+				}
+			}
+		}
+	}
+}
+
+func TestRandomizeCase(t *testing.T) {
+	for range 20 {
+		if got := randomizeCase("abc-DEF-123", language.Und); !strings.EqualFold(got, "abc-DEF-123") {
+			t.Errorf("expected a case variant of %q, but got %q", "abc-DEF-123", got)
+		}
+	}
+}
+
+func TestShuffleGenerator(t *testing.T) {
+	runes := []rune("abcdefghij")
+	for _, nchars := range []uint{0, 1, 5, 10} {
+		generator := newShuffleGenerator(runes, nchars)
+		for range 20 {
+			got := []rune(generator())
+			if len(got) != int(nchars) {
+				t.Fatalf("expected %v runes, but got %q", nchars, string(got))
+			}
+			slices.Sort(got)
+			if len(slices.Compact(got)) != len(got) {
+				t.Errorf("expected no duplicates, but got %q", string(got))
+			}
+			for _, r := range got {
+				if !slices.Contains(runes, r) {
+					t.Errorf("unexpected rune %q", r)
+				}
+			}
+		}
+	}
+}
+
+func TestApplyPepper(t *testing.T) {
+	orig := bytes.Repeat([]byte{0x5A}, 100)
+
+	buf1 := bytes.Clone(orig)
+	applyPepper(buf1, []byte("foo"))
+	buf2 := bytes.Clone(orig)
+	applyPepper(buf2, []byte("foo"))
+	buf3 := bytes.Clone(orig)
+	applyPepper(buf3, []byte("bar"))
+
+	if !bytes.Equal(buf1, buf2) {
+		t.Errorf("applyPepper is not deterministic")
+	}
+	if bytes.Equal(buf1, buf3) {
+		t.Errorf("applyPepper with different peppers returned the same result")
+	}
+	if bytes.Equal(buf1[64:], orig[64:]) {
+		t.Errorf("applyPepper did not transform the tail of the buffer")
+	}
+
+	applyPepper(buf1, []byte("foo"))
+	if !bytes.Equal(buf1, orig) {
+		t.Errorf("applyPepper is not an involution")
+	}
+}
+
+func TestCase_locale(t *testing.T) {
+	tests := []struct {
+		input  string
+		wcase  Case
+		locale string
+		want   string
+	}{
+		{"istanbul", CaseUpper, "und", "ISTANBUL"},
+		{"istanbul", CaseUpper, "tr", "İSTANBUL"},
+		{"ISPARTA", CaseLower, "und", "isparta"},
+		{"ISPARTA", CaseLower, "tr", "ısparta"},
+		{"straße", CaseUpper, "de", "STRASSE"},
+		{"STRASSE", CaseLower, "de", "strasse"},
+	}
+
+	for _, tt := range tests {
+		tag := language.MustParse(tt.locale)
+		if got := tt.wcase.apply(tt.input, tag); got != tt.want {
+			t.Errorf("Case(%v).apply(%q, %v): expected %q, but got %q", tt.wcase, tt.input, tt.locale, tt.want, got)
+		}
+	}
+
+	tag := language.MustParse("tr")
+	for range 20 {
+		for _, r := range randomizeCase("iiii", tag) {
+			if r != 'i' && r != 'İ' {
+				t.Errorf("randomizeCase(%q, tr): unexpected rune %q", "iiii", r)
+			}
+		}
+	}
+}
+
+func TestMixedCaseBounds(t *testing.T) {
+	tests := []struct {
+		input    string
+		locale   string
+		maxBytes uint
+		minRunes uint
+		maxRunes uint
+	}{
+		{"abc", "und", 3, 3, 3},
+		{"iii", "tr", 6, 3, 3},
+		{"straße", "de", 7, 6, 7},
+		{"ねこ", "und", 6, 2, 2},
+	}
+
+	for _, tt := range tests {
+		maxBytes, minRunes, maxRunes := mixedCaseBounds(tt.input, language.MustParse(tt.locale))
+		if maxBytes != tt.maxBytes || minRunes != tt.minRunes || maxRunes != tt.maxRunes {
+			t.Errorf("mixedCaseBounds(%q, %v): expected (%v, %v, %v), but got (%v, %v, %v)", tt.input, tt.locale, tt.maxBytes, tt.minRunes, tt.maxRunes, maxBytes, minRunes, maxRunes)
+		}
+	}
+}
+
+func TestAcrostic(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  string
+	}{
+		{nil, ""},
+		{[]string{"correct", "horse", "battery", "staple"}, "chbs"},
+		{[]string{"Élan", "", "ねこ"}, "Éね"},
+	}
+
+	for _, tt := range tests {
+		if got := acrostic(tt.words); got != tt.want {
+			t.Errorf("acrostic(%q): expected %q, but got %q", tt.words, tt.want, got)
+		}
+	}
+}
+
+func TestCountingReader(t *testing.T) {
+	saved := random
+	t.Cleanup(func() { random = saved })
+	cr := &countingReader{r: strings.NewReader(strings.Repeat("\x00", 16))}
+	random = cr
+
+	generator := newHexGenerator(4, nil)
+	for range 3 {
+		if got := generator(); got != "0000" {
+			t.Errorf("newHexGenerator(4): expected %q, but got %q", "0000", got)
+		}
+	}
+	if cr.n != 6 {
+		t.Errorf("countingReader: expected 6 bytes consumed, but got %v", cr.n)
+	}
+}
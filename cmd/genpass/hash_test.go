@@ -0,0 +1,73 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPassword_bcrypt(t *testing.T) {
+	hash, err := hashPassword("bcrypt", "correct horse")
+	if err != nil {
+		t.Fatalf("hashPassword: unexpected error: %v", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte("correct horse")); err != nil {
+		t.Errorf("hashPassword: %q does not match the password: %v", hash, err)
+	}
+	if cost, err := bcrypt.Cost([]byte(hash)); err != nil || cost != bcryptCost {
+		t.Errorf("hashPassword: expected cost %v, but got %v (%v)", bcryptCost, cost, err)
+	}
+
+	long := strings.Repeat("a", 72) + "b"
+	hash, err = hashPassword("bcrypt", long)
+	if err != nil {
+		t.Fatalf("hashPassword: unexpected error for a password longer than 72 bytes: %v", err)
+	}
+	sum := sha256.Sum256([]byte(long))
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(base64.StdEncoding.EncodeToString(sum[:]))); err != nil {
+		t.Errorf("hashPassword: %q does not match the pre-hashed password: %v", hash, err)
+	}
+}
+
+func TestHashPassword_argon2id(t *testing.T) {
+	hash, err := hashPassword("argon2id", "correct horse")
+	if err != nil {
+		t.Fatalf("hashPassword: unexpected error: %v", err)
+	}
+	var version, memory, time, threads int
+	var salt, key string
+	fields := strings.Split(hash, "$")
+	if len(fields) != 6 || fields[1] != "argon2id" {
+		t.Fatalf("hashPassword: malformed hash %q", hash)
+	}
+	salt, key = fields[4], fields[5]
+	if _, err := fmt.Sscanf(fields[2]+" "+fields[3], "v=%d m=%d,t=%d,p=%d", &version, &memory, &time, &threads); err != nil {
+		t.Fatalf("hashPassword: malformed parameters in %q: %v", hash, err)
+	}
+	rawSalt, err1 := base64.RawStdEncoding.DecodeString(salt)
+	rawKey, err2 := base64.RawStdEncoding.DecodeString(key)
+	if err1 != nil || err2 != nil {
+		t.Fatalf("hashPassword: malformed salt or key in %q", hash)
+	}
+	want := argon2.IDKey([]byte("correct horse"), rawSalt, uint32(time), uint32(memory), uint8(threads), uint32(len(rawKey)))
+	if subtle.ConstantTimeCompare(want, rawKey) != 1 {
+		t.Errorf("hashPassword: %q does not match the password", hash)
+	}
+
+	other, err := hashPassword("argon2id", "correct horse")
+	if err != nil {
+		t.Fatalf("hashPassword: unexpected error: %v", err)
+	}
+	if other == hash {
+		t.Errorf("hashPassword: expected a random salt, but got the same hash twice")
+	}
+}
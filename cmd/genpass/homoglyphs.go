@@ -0,0 +1,62 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"github.com/cions/genpass/internal/runeset"
+)
+
+// homoglyphGroups lists Latin, Cyrillic and Greek letters that are rendered
+// identically in common fonts, taken from the Unicode confusables data
+// (UTS #39, confusables.txt). The first member of each group is preferred.
+var homoglyphGroups = []string{
+	"aа",
+	"cс",
+	"dԁ",
+	"eе",
+	"hһ",
+	"iі",
+	"jј",
+	"oоο",
+	"pрρ",
+	"sѕ",
+	"vν",
+	"xх",
+	"yу",
+	"AАΑ",
+	"BВΒ",
+	"CС",
+	"EЕΕ",
+	"HНΗ",
+	"IІΙ",
+	"JЈ",
+	"KКΚ",
+	"MМΜ",
+	"NΝ",
+	"OОΟ",
+	"PРΡ",
+	"SЅ",
+	"TТΤ",
+	"XХΧ",
+	"YҮΥ",
+	"ZΖ",
+}
+
+func excludeHomoglyphs(set *runeset.RuneSet) runeset.RuneSet {
+	picker := set.Picker()
+	var homoglyphs runeset.RuneSet
+	for _, group := range homoglyphGroups {
+		var found bool
+		for _, r := range group {
+			if !picker.Contains(r) {
+				continue
+			}
+			if found {
+				homoglyphs.Add(r)
+			}
+			found = true
+		}
+	}
+	return set.Subtract(&homoglyphs)
+}
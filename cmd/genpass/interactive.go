@@ -0,0 +1,280 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cions/genpass/internal/runeset"
+	"golang.org/x/term"
+)
+
+var interactiveClasses = [...]struct {
+	key, name, cset string
+}{
+	{"l", "lowercase", `\l`},
+	{"u", "uppercase", `\L`},
+	{"d", "digits", `\d`},
+	{"s", "symbols", `\s`},
+}
+
+const interactiveBitsStep = 4
+
+type interactiveState struct {
+	base     *Command
+	variant  Variant
+	bits     uint
+	classes  [len(interactiveClasses)]bool
+	output   string
+	strength float64
+	err      error
+	status   string
+}
+
+func newInteractiveState(c *Command) *interactiveState {
+	s := &interactiveState{base: c, variant: c.Variant, bits: uint(math.Ceil(c.Bits))}
+	if s.bits == 0 {
+		s.bits = c.defaultBits(c.Variant)
+	}
+	for i := range s.classes {
+		s.classes[i] = true
+	}
+	s.regenerate()
+	return s
+}
+
+func (s *interactiveState) command() (*Command, error) {
+	c := *s.base
+	c.Logger = Logger{Writer: io.Discard}
+	c.Variant = s.variant
+	c.Bits = float64(s.bits)
+	c.Length = 0
+	if s.variant == Password {
+		var cset strings.Builder
+		for i, class := range interactiveClasses {
+			if s.classes[i] {
+				cset.WriteString(class.cset)
+			}
+		}
+		set, err := runeset.Parse(cset.String())
+		if err != nil {
+			return nil, err
+		}
+		if set.Picker().Size() < 2 {
+			return nil, errors.New("no character classes are selected")
+		}
+		c.Charset = &set
+		c.CharsetSpec = cset.String()
+	}
+	return &c, nil
+}
+
+func (s *interactiveState) regenerate() {
+	s.output, s.strength, s.err = "", 0, nil
+	c, err := s.command()
+	if err != nil {
+		s.err = err
+		return
+	}
+	generator, bits, err := c.getGenerator()
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.output, s.strength = generator(), bits
+}
+
+func (s *interactiveState) handleKey(key string) (quit bool) {
+	s.status = ""
+	switch key {
+	case "q", "\x1b", "\x03", "\x04":
+		return true
+	case "v":
+		s.variant = (s.variant + 1) % Variant(len(variants))
+	case "+", "=", "\x1b[A":
+		s.bits += interactiveBitsStep
+	case "-", "\x1b[B":
+		if s.bits > interactiveBitsStep {
+			s.bits -= interactiveBitsStep
+		}
+	case "c":
+		if s.output != "" {
+			s.status = "copied to the clipboard"
+		}
+		return false
+	case " ", "r", "\r":
+	default:
+		for i, class := range interactiveClasses {
+			if key == class.key {
+				s.classes[i] = !s.classes[i]
+			}
+		}
+	}
+	s.regenerate()
+	return false
+}
+
+func (s *interactiveState) render(w io.Writer) {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&b, "variant: %v    bits: %d\r\n", variants[s.variant].Name, s.bits)
+	if s.variant == Password {
+		b.WriteString("classes:")
+		for i, class := range interactiveClasses {
+			mark := " "
+			if s.classes[i] {
+				mark = "x"
+			}
+			fmt.Fprintf(&b, " [%v] %v (%v)", mark, class.name, class.key)
+		}
+		b.WriteString("\r\n")
+	}
+	b.WriteString("\r\n")
+	if s.err != nil {
+		fmt.Fprintf(&b, "  error: %v\r\n", s.err)
+	} else {
+		fmt.Fprintf(&b, "  %v\r\n", s.output)
+	}
+	b.WriteString("\r\n")
+	fmt.Fprintf(&b, "strength: %.2f bits\r\n", s.strength)
+	b.WriteString("keys: v variant, +/- bits, l/u/d/s classes, space regenerate, c copy, q quit\r\n")
+	if s.status != "" {
+		fmt.Fprintf(&b, "%v\r\n", s.status)
+	}
+	io.WriteString(w, b.String())
+}
+
+func copyToClipboard(w io.Writer, s string) {
+	fmt.Fprintf(w, "\x1b]52;c;%v\a", base64.StdEncoding.EncodeToString([]byte(s)))
+}
+
+// escapeTimeout is how long a lone ESC waits for the rest of an escape
+// sequence before it is taken as a key on its own.
+const escapeTimeout = 50 * time.Millisecond
+
+// nextKey splits the first key off buf. ok is false if buf may hold only
+// the beginning of a key, as when an escape sequence is split across reads.
+func nextKey(buf []byte) (key string, rest []byte, ok bool) {
+	switch {
+	case len(buf) == 0:
+		return "", buf, false
+	case buf[0] != '\x1b':
+		if !utf8.FullRune(buf) {
+			return "", buf, false
+		}
+		_, size := utf8.DecodeRune(buf)
+		return string(buf[:size]), buf[size:], true
+	case len(buf) == 1:
+		return "", buf, false
+	case buf[1] == '[':
+		// A CSI sequence ends with a byte in the range 0x40-0x7E.
+		for i := 2; i < len(buf); i++ {
+			if 0x40 <= buf[i] && buf[i] <= 0x7e {
+				return string(buf[:i+1]), buf[i+1:], true
+			}
+		}
+		return "", buf, false
+	case buf[1] == 'O':
+		if len(buf) < 3 {
+			return "", buf, false
+		}
+		return string(buf[:3]), buf[3:], true
+	default:
+		return "\x1b", buf[1:], true
+	}
+}
+
+type readResult struct {
+	data []byte
+	err  error
+}
+
+// readInput sends what is read from r until r fails or done is closed.
+func readInput(r io.Reader, done <-chan struct{}) <-chan readResult {
+	ch := make(chan readResult)
+	go func() {
+		for {
+			buf := make([]byte, 16)
+			n, err := r.Read(buf)
+			select {
+			case ch <- readResult{buf[:n], err}:
+			case <-done:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// loop redraws the screen on w and handles the keys read from r until a key
+// quits.
+func (s *interactiveState) loop(r io.Reader, w io.Writer) error {
+	done := make(chan struct{})
+	defer close(done)
+	input := readInput(r, done)
+
+	var pending []byte
+	var readErr error
+	s.render(w)
+	for {
+		key, rest, ok := nextKey(pending)
+		if !ok {
+			switch {
+			case readErr != nil && len(pending) == 0:
+				return readErr
+			case readErr != nil:
+				key, rest = string(pending), nil
+			default:
+				var timeout <-chan time.Time
+				if len(pending) != 0 && pending[0] == '\x1b' {
+					timeout = time.After(escapeTimeout)
+				}
+				select {
+				case res := <-input:
+					pending, readErr = append(pending, res.data...), res.err
+					continue
+				case <-timeout:
+					key, rest = string(pending), nil
+				}
+			}
+		}
+		pending = rest
+		if s.handleKey(key) {
+			return nil
+		}
+		if key == "c" && s.output != "" {
+			copyToClipboard(w, s.output)
+		}
+		s.render(w)
+	}
+}
+
+func (c *Command) runInteractive() error {
+	stdin, ok := c.Stdin.(*os.File)
+	if !ok || !term.IsTerminal(int(stdin.Fd())) {
+		return errors.New("--interactive requires a terminal")
+	}
+	if stdout, ok := c.Stdout.(*os.File); !ok || !term.IsTerminal(int(stdout.Fd())) {
+		return errors.New("--interactive requires a terminal")
+	}
+	fd := int(stdin.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, oldState)
+
+	return newInteractiveState(c).loop(c.Stdin, c.Stdout)
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestInteractiveState(t *testing.T) {
+	c := &Command{Wordlist: "eff-large", WordlistFormat: "plain", Separator: " "}
+	s := newInteractiveState(c)
+	if s.err != nil || s.output == "" || s.strength < 80 {
+		t.Fatalf("unexpected initial state: %q, %v, %v", s.output, s.strength, s.err)
+	}
+
+	s.handleKey("v")
+	if s.variant != Password || s.err != nil || s.output == "" {
+		t.Fatalf("unexpected state after v: %v, %q, %v", s.variant, s.output, s.err)
+	}
+
+	s.handleKey("+")
+	if s.bits != 84 || s.strength < 84 {
+		t.Errorf("expected at least 84 bits, but got %v (%v)", s.strength, s.bits)
+	}
+
+	s.handleKey("u")
+	s.handleKey("s")
+	if strings.ContainsFunc(s.output, func(r rune) bool { return r < '0' || r > '9' && r < 'a' || r > 'z' }) {
+		t.Errorf("expected lowercase letters and digits, but got %q", s.output)
+	}
+	s.handleKey("l")
+	s.handleKey("d")
+	if s.err == nil {
+		t.Errorf("expected an error with no character classes")
+	}
+
+	var b strings.Builder
+	s.render(&b)
+	if !strings.Contains(b.String(), "variant: password") {
+		t.Errorf("unexpected screen: %q", b.String())
+	}
+
+	if !s.handleKey("q") {
+		t.Errorf("expected q to quit")
+	}
+}
+
+func TestInteractiveState_loop(t *testing.T) {
+	c := &Command{Wordlist: "eff-large", WordlistFormat: "plain", Separator: " "}
+	s := newInteractiveState(c)
+	var out bytes.Buffer
+	if err := s.loop(iotest.OneByteReader(strings.NewReader("v+cq")), &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.variant != Password || s.bits != 84 {
+		t.Errorf("unexpected state: %v, %v", s.variant, s.bits)
+	}
+	if !strings.Contains(out.String(), "\x1b]52;c;") || !strings.Contains(out.String(), "copied to the clipboard") {
+		t.Errorf("expected the output to be copied, but got %q", out.String())
+	}
+
+	s = newInteractiveState(c)
+	if err := s.loop(strings.NewReader(""), io.Discard); err != io.EOF {
+		t.Errorf("expected %v, but got %v", io.EOF, err)
+	}
+}
+
+func TestNextKey(t *testing.T) {
+	tests := []struct {
+		buf  string
+		key  string
+		rest string
+		ok   bool
+	}{
+		{"", "", "", false},
+		{"q+", "q", "+", true},
+		{"\xc3", "", "\xc3", false},
+		{"\x1b", "", "\x1b", false},
+		{"\x1b[", "", "\x1b[", false},
+		{"\x1b[1;5", "", "\x1b[1;5", false},
+		{"\x1b[Aq", "\x1b[A", "q", true},
+		{"\x1b[1;5Bq", "\x1b[1;5B", "q", true},
+		{"\x1bO", "", "\x1bO", false},
+		{"\x1bOA", "\x1bOA", "", true},
+		{"\x1bq", "\x1b", "q", true},
+	}
+	for _, tt := range tests {
+		key, rest, ok := nextKey([]byte(tt.buf))
+		if key != tt.key || string(rest) != tt.rest || ok != tt.ok {
+			t.Errorf("nextKey(%q): expected %q, %q, %v, but got %q, %q, %v", tt.buf, tt.key, tt.rest, tt.ok, key, rest, ok)
+		}
+	}
+}
+
+func TestInteractiveState_loopEscape(t *testing.T) {
+	c := &Command{Wordlist: "eff-large", WordlistFormat: "plain", Separator: " "}
+
+	// Arrow keys split across reads must not be taken as ESC.
+	s := newInteractiveState(c)
+	if err := s.loop(iotest.OneByteReader(strings.NewReader("\x1b[A\x1b[A\x1b[Bq")), io.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.bits != 84 {
+		t.Errorf("expected 84 bits, but got %v", s.bits)
+	}
+
+	// A lone ESC quits once no sequence follows it.
+	r, w := io.Pipe()
+	defer w.Close()
+	go w.Write([]byte("\x1b"))
+	errc := make(chan error, 1)
+	go func() { errc <- newInteractiveState(c).loop(r, io.Discard) }()
+	select {
+	case err := <-errc:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected ESC to quit")
+	}
+}
+
+func TestRun_interactiveNoTerminal(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	args := []string{"--interactive"}
+	if err := run(args, strings.NewReader("q"), io.Discard, io.Discard); err == nil || !strings.Contains(err.Error(), "terminal") {
+		t.Errorf("run(%q): expected an error about the terminal, but got %v", args, err)
+	}
+}
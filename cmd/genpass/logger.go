@@ -0,0 +1,29 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+type Logger struct {
+	Writer  io.Writer
+	Quiet   bool
+	Verbose bool
+}
+
+func (l *Logger) Debugf(format string, a ...any) {
+	if !l.Verbose {
+		return
+	}
+	fmt.Fprintf(l.Writer, "%v: %v\n", NAME, fmt.Sprintf(format, a...))
+}
+
+func (l *Logger) Warnf(format string, a ...any) {
+	if l.Quiet {
+		return
+	}
+	fmt.Fprintf(l.Writer, "%v: warning: %v\n", NAME, fmt.Sprintf(format, a...))
+}
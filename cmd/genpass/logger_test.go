@@ -0,0 +1,55 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cions/go-options"
+)
+
+func TestLogger_quiet(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{}, NAME + ": warning: weak\n"},
+		{[]string{"-q"}, ""},
+		{[]string{"--quiet"}, ""},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		c := &Command{Logger: Logger{Writer: &b}}
+		if _, err := options.Parse(c, tt.args); err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", tt.args, err)
+		}
+		c.Warnf("weak")
+		if got := b.String(); got != tt.want {
+			t.Errorf("Parse(%q): expected %q, but got %q", tt.args, tt.want, got)
+		}
+	}
+}
+
+func TestLogger_verbose(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{}, ""},
+		{[]string{"-v"}, NAME + ": size: 4\n"},
+		{[]string{"--verbose"}, NAME + ": size: 4\n"},
+	}
+	for _, tt := range tests {
+		var b bytes.Buffer
+		c := &Command{Logger: Logger{Writer: &b}}
+		if _, err := options.Parse(c, tt.args); err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", tt.args, err)
+		}
+		c.Debugf("size: %v", 4)
+		if got := b.String(); got != tt.want {
+			t.Errorf("Parse(%q): expected %q, but got %q", tt.args, tt.want, got)
+		}
+	}
+}
@@ -5,19 +5,29 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"math"
 	"os"
+	"os/signal"
+	"runtime"
 	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/cions/genpass/internal/runeset"
 	"github.com/cions/genpass/internal/wordlists"
 	"github.com/cions/go-colorterm"
 	"github.com/cions/go-options"
+	"golang.org/x/text/language"
 )
 
 var NAME = "genpass"
@@ -27,23 +37,328 @@ var USAGE = `Usage: $NAME [-e] [-c N] [-w WORDLIST | -p | -x | -u] [-b BITS | -l
 Generates secure random passphrases/password/hex/base64 strings.
 
 Options:
-  -e, --show-bits       Show the password strength
-  -c, --count=N         Generate N strings
+  -e, --show-bits       Show the password strength, in red if it is below
+                        64 bits, in yellow up to 100 bits, and in green
+                        above 100 bits
+      --bits-summary    Show the strength once on the standard error
+                        before the strings instead of on every line
+                        (implies --show-bits)
+      --estimate        Add the average time to guess the string offline
+                        to --show-bits (implies --show-bits)
+      --guess-rate=RATE Assume RATE guesses per second for --estimate
+                        (default: 1e10)
+      --bits-base={2|e|10}
+                        Show the strength with --show-bits in bits (2),
+                        nats (e) or decimal digits (10) (default: 2)
+      --no-color        Do not color the output (also disabled by NO_COLOR)
+      --preview         Color digits and symbols in the generated strings
+                        differently from letters
+      --show-entropy-bytes
+                        Print the random bytes drawn for each string to
+                        stderr in hex (diagnostic; exposes the secret)
+  -q, --quiet           Suppress warning messages
+  -v, --verbose         Show diagnostic messages to stderr
+  -c, --count=N         Generate N strings (0: until the output is closed)
+      --delimiter-between-results=DELIM
+                        Output a line of DELIM (e.g. ---, or an empty line
+                        if DELIM is empty) between the strings of --count
+      --unique          Make the N strings of --count distinct from each
+                        other, failing if there are fewer possible strings
+      --progress        Show how many of the strings of --count have been
+                        generated on the standard error (only if it is a
+                        terminal, and not with --quiet)
+      --also=VARIANT[:BITS]
+                        Also generate a string of VARIANT (passphrase,
+                        password, hex or base64) with BITS-bit strength
+                        (default: the default of the variant) each time.
+                        Can be repeated. Each output line is prefixed with
+                        the variant name and a tab.
+      --count-from=N    Prefix each string with its index and a tab,
+                        counting from N (to continue numbering across runs)
   -b, --bits=BITS       Generate strings with at least BITS-bit strength
                         (default: 80-bit for passphrase/password,
                                   128-bit for hex/base64)
+      --default-bits-{passphrase|password|hex|base64}=BITS
+                        Change the default of --bits for the variant,
+                        which is useful in the configuration file
+      --strength-preset={weak|standard|strong|paranoid}
+                        Use a named strength instead of --bits
+                        (weak: 64-bit, standard: 80-bit, strong: 112-bit,
+                         paranoid: 160-bit; ignored if -b or -l is given
+                         on the command line)
   -l, --length=N        Generate N-words/characters strings
-  -w, --wordlist={eff-large|eff-short1|eff-short2|bip39|slip39|FILE}
+      --length-bits={max|length|bits}
+                        When both --length and --bits are given, generate
+                        the longer of the two lengths (max), or use only
+                        --length or only --bits (default: max); if only one
+                        is on the command line, that one is used
+      --max-bytes=N     Limit each string to at most N bytes in UTF-8
+                        (passphrases and passwords are made short enough
+                        for their longest words or widest characters;
+                        words longer than N bytes are never used)
+  -w, --wordlist={eff-large|eff-short1|eff-short2|bip39|slip39|adjectives|nouns|verbs|FILE|exec:COMMAND}
                         Generate passphrases using the specified wordlist
                         (default: eff-large)
+                        exec:COMMAND reads the wordlist from the standard
+                        output of COMMAND (split at whitespace, not run by
+                        a shell), which must exit successfully within 30
+                        seconds and print at most 64 MiB
+      --wordlist-cache=TTL
+                        Cache the output of exec:COMMAND wordlists for TTL
+                        (e.g. 24h) in the user cache directory
+      --no-cache        Do not use the wordlist cache
+      --refresh         Rerun exec:COMMAND wordlists and update the cache
+      --wordlist-format={plain|tsv}
+                        Format of the wordlist FILE (default: plain)
+                        tsv: each line is WORD<TAB>FREQUENCY, and words are
+                        chosen with probability proportional to FREQUENCY.
+                        Strength is computed from the Shannon entropy of the
+                        frequencies, which is lower than for a uniform list
+                        of the same size, so more words are generated.
+      --wordlist-column=N
+                        Use the N-th column of each line of the wordlist FILE
+                        (columns are separated by whitespace by default)
+      --wordlist-sep=SEP
+                        Separate columns of the wordlist FILE by SEP
+      --allow-empty-wordlist-lines
+                        Keep empty and whitespace-only lines of the
+                        wordlist FILE as words (by default they are skipped)
+      --trim-wordlist-whitespace={both|trailing|none}
+                        Remove leading and trailing (default), only
+                        trailing, or no whitespace from words of the
+                        wordlist FILE
+      --no-ambiguous-words
+                        Remove words that are easily confused when spoken
+                        or typed from the wordlist: words differing from an
+                        earlier word by one letter, and all but the first
+                        of a group of common English homophones. The
+                        strength is computed from the remaining words.
+      --ambiguous-words-file=FILE
+                        Also remove the words listed in FILE (one per line)
+                        (implies --no-ambiguous-words)
+      --passphrase-min-word-len-in-output=K
+                        Use only the words of at least K characters from
+                        the wordlist. The strength is computed from the
+                        remaining words.
+      --passphrase-digits=N
+                        Insert N random digits at random positions among
+                        the words of passphrases
+      --append-from=CSET
+                        Append random characters from CSET to the end of
+                        passphrases (e.g. to satisfy symbol requirements)
+      --append-count=N  Append N characters with --append-from (default: 1)
+      --passphrase-length-chars=N
+                        Add words to passphrases as long as they fit in N
+                        characters (strength counts only the words that
+                        always fit)
+      --pattern=WORDLIST,...
+                        Generate passphrases drawing the i-th word from the
+                        i-th wordlist, repeating the pattern as needed
+                        (adj, noun and verb are short for adjectives, nouns
+                        and verbs, e.g. --pattern=adj,noun,verb)
+      --passphrase-template=TEMPLATE
+                        Generate passphrases from TEMPLATE, replacing each
+                        placeholder with a random element: {adj}, {noun},
+                        {verb} or {WORDLIST} (a built-in wordlist) with a
+                        word, {adverb} with an adverb, {determiner} with
+                        a word like "the" or "every", {number} with 0-99,
+                        {digit} with 0-9 and {symbol} with an ASCII
+                        punctuation. Use {{ and }} for literal braces
+                        (e.g. "{adj} {noun} {number}")
+      --passphrase-from-sentence
+                        Generate passphrases of sentences like "The quick
+                        otter will jump loudly." (--length sets the number
+                        of sentences). The fixed words add no strength.
+      --slip39-share    Generate single-share (1-of-1) SLIP39 mnemonics of
+                        a random 128-bit or 256-bit secret with a valid
+                        checksum (20 or 33 words)
+  -s, --separator=SEP   Separate words of passphrases by SEP (default: " ")
+      --words-only      Output each word of passphrases on its own line,
+                        with an empty line between passphrases (cannot be
+                        used with -s)
+      --passphrase-acrostic
+                        Show the first letters of the words after each
+                        passphrase as a memory aid (adds no strength)
+      --case={lower|upper|mixed}
+                        Convert words of passphrases to lowercase (default)
+                        or uppercase, or make each letter uppercase or
+                        lowercase at random (mixed). Words that differ only
+                        in case are counted once. For mixed, only the
+                        letters of the shortest word in the wordlist are
+                        counted toward the strength, so it is a lower bound.
+      --randomize-case  Same as --case=mixed
+      --locale=TAG      Apply the casing rules of the language TAG (e.g. tr
+                        or de) for --case, instead of language-neutral ones
   -p, --password        Generate passwords using ASCII graphical characters
   -P, --password-with=CSET
                         Generate passwords using characters specified by CSET
+      --first-char-class=CSET
+                        Draw the first character of passwords from CSET
+      --last-char-class=CSET
+                        Draw the last character of passwords from CSET
+      --insert=POS:CSET Draw the POS-th character (1-based) of passwords
+                        from CSET instead of the charset. Can be repeated
+      --printable-only  Exclude characters other than letters, numbers,
+                        punctuations and symbols from passwords
+      --exclude-homoglyphs
+                        Exclude Cyrillic and Greek letters that look the
+                        same as a Latin letter (or each other) in the
+                        charset, keeping one of each group (based on the
+                        Unicode confusables data)
+      --ensure-printable-ascii
+                        Fail if a generated string contains a character
+                        other than printable ASCII (U+0020 to U+007E)
+      --allowed-chars-report
+                        Warn which characters of the charset may need
+                        quoting or escaping in shells, CSV or URLs
+      --min-classes=N   Generate passwords containing characters from at
+                        least N of 4 classes (lowercase letters, uppercase
+                        letters, digits and others) by regenerating them.
+                        The strength is reduced accordingly.
+      --min-unique-chars=N
+                        Generate passwords containing at least N distinct
+                        characters by regenerating them. The strength is
+                        reduced accordingly (negligibly unless N is close
+                        to the length).
+      --min-digits=N    Generate passwords containing at least N digits by
+                        regenerating them. The strength is reduced
+                        accordingly.
+      --min-symbols=N   Generate passwords containing at least N symbols
+                        (characters other than letters and digits) by
+                        regenerating them. The strength is reduced
+                        accordingly.
+      --reject-sequential
+                        Regenerate passwords containing 3 or more
+                        sequential characters, such as abc, 321 or qwe
+                        (case-insensitive). The strength is reduced
+                        slightly, which is accounted for.
+      --retry-limit=N   Give up after regenerating a string N times to
+                        satisfy constraints such as --min-classes
+                        (default: 10000)
+      --shuffle         Generate a random permutation of the charset of
+                        -p/-P, so no character appears more than once.
+                        With -l N or -b N, only the first characters of the
+                        permutation are output.
+      --weighted        Make characters of CSET that are given N times
+                        (e.g. \d in -P '\d\d\l') N times as likely. The
+                        strength is the Shannon entropy of the resulting
+                        distribution, which is lower than that of the
+                        uniform one.
+      --assert-no-dupes-in-charset
+                        Fail if CSET of -P lists a character more than once
+                        (e.g. -P 'a-zaeiou'), which biases --weighted
+      --emoji           Generate strings of emoji
+      --alphabet-file=FILE
+                        Generate strings of the characters in FILE. Line
+                        breaks are ignored and duplicate characters are
+                        rejected.
+      --charset-spec=FILE
+                        Generate strings of the charset built by the lines
+                        of FILE in order: +CSET adds, -CSET removes and
+                        &CSET keeps only the characters of CSET. Empty
+                        lines and lines starting with # are ignored.
   -x, --hex             Generate hexadecimal strings
   -u, --base64          Generate base64url strings
+      --pepper=SECRET   XOR the random bytes of hex/base64 strings with a
+                        keystream derived from SECRET by HMAC-SHA256. This
+                        is a bijection, so it neither adds nor removes
+                        strength; it only makes the output depend on SECRET.
+      --split=N         Output N shares of each hex/base64 string instead
+                        of the string, labeled "share K/N" (or "secret M
+                        share K/N" if -c is not 1), that give it back when
+                        XORed together digit by digit. This is N-of-N
+                        splitting: all N shares are needed and any N-1 of
+                        them reveal nothing about the string.
+      --entropy-source=file:PATH
+                        Read random bytes from PATH (e.g. a hardware RNG
+                        device) instead of the operating system's CSPRNG.
+                        The output is only as unpredictable as PATH, and
+                        generation fails if PATH cannot supply enough bytes
+      --derive          Derive strings deterministically from --master and
+                        --site instead of generating them at random. The
+                        key is the 32-byte Argon2id (t=3, m=64MiB, p=4) of
+                        SECRET with the salt "genpass-derive-v1:" followed
+                        by NAME, and the random bytes are HMAC-SHA256(key,
+                        counter). The output is only as strong as SECRET,
+                        whatever the reported strength.
+      --site=NAME       Site name for --derive
+      --master=SECRET   Master secret for --derive
+      --test-seed=N     Generate reproducible passphrases for test fixtures
+                        from a PRNG seeded with N. The output is NOT SECRET:
+                        anyone who knows N can reproduce it. Requires
+                        --i-know-this-is-insecure
+      --i-know-this-is-insecure
+                        Confirm that the output of --test-seed must not be
+                        used as a secret
+      --hyphenate-every=N
+                        Insert a hyphen after every N characters
+      --hyphenate-with=DELIM
+                        Use DELIM instead of a hyphen for --hyphenate-every
+      --hash={bcrypt|argon2id}
+                        Output a hash of each string after it, separated by
+                        a tab, to store while the string is handed out
+                        (bcrypt: cost 12; argon2id: t=3, m=64MiB, p=4).
+                        bcrypt only uses 72 bytes, so longer strings are
+                        hashed as the base64 of their SHA-256 digest, and
+                        must be pre-hashed the same way to verify them.
+      --wrap=COLS       Wrap the output every COLS characters, counting
+                        escape sequences of --output-encoding in full
+      --output-encoding={utf8|ascii-escaped}
+                        Output the strings as UTF-8 (default) or with every
+                        non-ASCII character as \uXXXX or \UXXXXXXXX and
+                        backslashes as \\. The escaped form is longer and
+                        must be decoded by the consumer before use.
+      --histogram=N     Draw N characters from the charset of -p/-P, print
+                        how many times each character was drawn and exit
+                        (diagnostic)
+      --sample=N        Print N randomly chosen words of the wordlist or
+                        characters of the charset, one per line, and exit.
+                        The words are filtered and cased as in passphrases.
+      --mnemonic-checksum
+                        Read a BIP39 mnemonic from stdin, verify its checksum
+                        and print the entropy, then exit
+      --wordlist-check  Check whether no word in the wordlist is a prefix of
+                        another, which is needed for passphrases without
+                        separators to be unambiguous, then exit
+      --wordlist-stats-json
+                        Print statistics of the wordlist (number of words,
+                        bits per word, word length distribution and whether
+                        it is prefix-free) in JSON, then exit
+      --debug-charset   Print the ranges of the charset of -p/-P as parsed,
+                        before and after merging adjacent ranges, then exit
+      --list-variants   Print the variants and their default strength,
+                        then exit
+      --interactive     Open a terminal UI to choose the variant, strength
+                        and character classes while watching the result
+      --config=FILE     Read default options from FILE
+                        (default: $CONFIG)
   -h, --help            Show this help message and exit
       --version         Show version information and exit
 
+Environment variables:
+  GENPASS_WORDLIST      Default value of --wordlist
+  GENPASS_BITS          Default value of --bits
+  GENPASS_SEPARATOR     Default value of --separator
+  Empty variables are ignored.
+
+Configuration file:
+  Each line is KEY = VALUE, where KEY is a long option name without the
+  leading dashes (e.g. wordlist = "eff-short1", bits = 100, show-bits = true)
+  or variant = {passphrase|password|hex|base64}. Lines starting with # are
+  ignored. Command-line options take precedence over environment variables,
+  which take precedence over the configuration file. Unlike -w, a wordlist
+  given there or in GENPASS_WORDLIST does not select the passphrase variant.
+  The secrets master and pepper, and the insecure test-seed and
+  i-know-this-is-insecure, can be given only on the command line.
+
+Exit status:
+  0  success
+  1  other errors
+  2  invalid command-line options (or configuration/environment)
+  3  the random source failed
+  4  the constraints (e.g. --min-classes, --max-bytes) cannot be satisfied
+  5  a file could not be read or written
+
 Syntax of CSET:
         c               Character c
         \-              Literal -
@@ -60,9 +375,74 @@ Syntax of CSET:
         \g              ASCII graphical characters
         \pN             Unicode character class (one-letter General Category)
         \p{NAME}        Unicode character class (General Category or Scripts)
+        \p{IsBLOCK}     Unicode block (e.g. \p{IsBasicLatin}, \p{IsCyrillic};
+                        spaces, hyphens, underscores and case are ignored)
 `
 
-var Gray = colorterm.Fg256Color(245)
+const (
+	weakBits   = 64
+	strongBits = 100
+)
+
+func crackTime(bits, rate float64) string {
+	seconds := math.Exp2(bits-1) / rate
+	units := []struct {
+		name    string
+		seconds float64
+	}{
+		{"years", 365.25 * 24 * 60 * 60},
+		{"days", 24 * 60 * 60},
+		{"hours", 60 * 60},
+		{"minutes", 60},
+		{"seconds", 1},
+	}
+	switch {
+	case seconds < 1:
+		return "<1 second"
+	case seconds >= 1000*units[0].seconds:
+		return fmt.Sprintf("~%.0e years", seconds/units[0].seconds)
+	}
+	for _, unit := range units {
+		if seconds >= unit.seconds {
+			n := math.Round(seconds / unit.seconds)
+			if n == 1 {
+				return "~1 " + strings.TrimSuffix(unit.name, "s")
+			}
+			return fmt.Sprintf("~%.0f %v", n, unit.name)
+		}
+	}
+	panic("unreachable")
+}
+
+func formatStrength(bits float64, base string) string {
+	switch base {
+	case "e":
+		return fmt.Sprintf("%.2f nats", bits*math.Ln2)
+	case "10":
+		return fmt.Sprintf("%.2f decimal digits", bits*math.Log10(2))
+	default:
+		return fmt.Sprintf("%.2f bits", bits)
+	}
+}
+
+func (c *Command) strength(bits float64) string {
+	s := formatStrength(bits, c.BitsBase)
+	if c.Estimate {
+		s += ", " + crackTime(bits, c.GuessRate)
+	}
+	return s
+}
+
+func bitsColor(bits float64) colorterm.EscapeCode {
+	switch {
+	case bits < weakBits:
+		return colorterm.FgRed
+	case bits <= strongBits:
+		return colorterm.FgYellow
+	default:
+		return colorterm.FgGreen
+	}
+}
 
 type Variant int
 
@@ -73,36 +453,303 @@ const (
 	Base64
 )
 
+type alsoSpec struct {
+	Variant Variant
+	Bits    float64
+}
+
+type insertSpec struct {
+	Pos   uint
+	Chars *runeset.RuneSet
+}
+
+type labeledGenerator struct {
+	Label     string
+	Generator Generator
+	Bits      float64
+}
+
+type variantInfo struct {
+	Name        string
+	DefaultBits uint
+	Generator   func(c *Command, defaultBits uint) (Generator, float64, error)
+}
+
+var variants = [...]variantInfo{
+	Passphrase:  {"passphrase", 80, (*Command).getPassphraseGenerator},
+	Password:    {"password", 80, (*Command).getPasswordGenerator},
+	Hexadecimal: {"hex", 128, (*Command).getHexGenerator},
+	Base64:      {"base64", 128, (*Command).getBase64Generator},
+}
+
 type Command struct {
-	ShowBits bool
-	Count    uint
-	Variant  Variant
-	Bits     uint
-	Length   uint
-	Wordlist string
-	Picker   *runeset.Picker
+	Logger
+	Stdin                io.Reader
+	Stdout               io.Writer
+	ShowBits             bool
+	Preview              bool
+	Estimate             bool
+	BitsSummary          bool
+	GuessRate            float64
+	BitsBase             string
+	ShowEntropyBytes     bool
+	Count                uint
+	Unique               bool
+	Progress             bool
+	ResultDelimiter      *string
+	Numbered             bool
+	CountFrom            uint
+	Variant              Variant
+	Also                 []alsoSpec
+	Bits                 float64
+	DefaultBits          [4]uint
+	StrengthPreset       uint
+	Length               uint
+	LengthBits           string
+	MaxBytes             uint
+	Digits               uint
+	MinWordLen           uint
+	LengthChars          uint
+	AppendChars          *runeset.RuneSet
+	AppendCount          uint
+	Wordlist             string
+	Pattern              []string
+	Template             []templatePart
+	Sentence             bool
+	Separator            string
+	WordsOnly            bool
+	Acrostic             bool
+	Case                 Case
+	Locale               language.Tag
+	SLIP39Share          bool
+	WordlistFormat       string
+	WordlistCacheTTL     time.Duration
+	NoCache              bool
+	RefreshCache         bool
+	WordlistColumn       uint
+	WordlistSep          string
+	AllowEmptyLines      bool
+	TrimWhitespace       string
+	NoAmbiguousWords     bool
+	AmbiguousWordsFile   string
+	Charset              *runeset.RuneSet
+	CharsetSpec          string
+	FirstChars           *runeset.RuneSet
+	LastChars            *runeset.RuneSet
+	Inserts              []insertSpec
+	PrintableOnly        bool
+	ExcludeHomoglyphs    bool
+	EnsurePrintableASCII bool
+	AllowedCharsReport   bool
+	MinClasses           uint
+	RejectSequential     bool
+	MinUniqueChars       uint
+	MinDigits            uint
+	MinSymbols           uint
+	RetryLimit           uint
+	Shuffle              bool
+	Weighted             bool
+	AssertNoDupes        bool
+	HyphenateEvery       uint
+	HyphenateWith        string
+	Wrap                 uint
+	EscapeOutput         bool
+	Hash                 string
+	Pepper               []byte
+	Split                uint
+	Derive               bool
+	EntropySource        string
+	TestSeed             *uint64
+	Insecure             bool
+	Site                 string
+	Master               string
+	Histogram            uint
+	Sample               uint
+	MnemonicChecksum     bool
+	WordlistCheck        bool
+	WordlistStatsJSON    bool
+	DebugCharset         bool
+	Interactive          bool
+	ListVariants         bool
+	Benchmark            uint
+	given                []string
+	acrostic             func(string) string
 }
 
 func (c *Command) Kind(name string) options.Kind {
 	switch name {
 	case "-e", "--show-bits":
 		return options.Boolean
+	case "--estimate":
+		return options.Boolean
+	case "--bits-summary":
+		return options.Boolean
+	case "--guess-rate":
+		return options.Required
+	case "--bits-base":
+		return options.Required
+	case "--no-color":
+		return options.Boolean
+	case "--preview":
+		return options.Boolean
+	case "--show-entropy-bytes":
+		return options.Boolean
+	case "-q", "--quiet":
+		return options.Boolean
+	case "-v", "--verbose":
+		return options.Boolean
 	case "-c", "--count":
 		return options.Required
+	case "--count-from":
+		return options.Required
+	case "--unique":
+		return options.Boolean
+	case "--progress":
+		return options.Boolean
+	case "--delimiter-between-results":
+		return options.Required
+	case "--also":
+		return options.Required
 	case "-b", "--bits":
 		return options.Required
+	case "--default-bits-passphrase", "--default-bits-password", "--default-bits-hex", "--default-bits-base64":
+		return options.Required
+	case "--strength-preset":
+		return options.Required
+	case "--length-bits":
+		return options.Required
 	case "-l", "--length":
 		return options.Required
+	case "--max-bytes":
+		return options.Required
+	case "--passphrase-digits":
+		return options.Required
+	case "--passphrase-min-word-len-in-output":
+		return options.Required
+	case "--passphrase-length-chars":
+		return options.Required
+	case "--append-from", "--append-count":
+		return options.Required
 	case "-w", "--wordlist":
 		return options.Required
+	case "--wordlist-cache":
+		return options.Required
+	case "--no-cache", "--refresh":
+		return options.Boolean
+	case "--wordlist-format":
+		return options.Required
+	case "--wordlist-column", "--wordlist-sep":
+		return options.Required
+	case "--allow-empty-wordlist-lines":
+		return options.Boolean
+	case "--trim-wordlist-whitespace":
+		return options.Required
+	case "--no-ambiguous-words":
+		return options.Boolean
+	case "--ambiguous-words-file":
+		return options.Required
+	case "--pattern":
+		return options.Required
+	case "--passphrase-template":
+		return options.Required
+	case "--passphrase-from-sentence":
+		return options.Boolean
+	case "--case":
+		return options.Required
+	case "--randomize-case":
+		return options.Boolean
+	case "--locale":
+		return options.Required
+	case "--slip39-share":
+		return options.Boolean
+	case "-s", "--separator":
+		return options.Required
+	case "--words-only":
+		return options.Boolean
+	case "--passphrase-acrostic":
+		return options.Boolean
 	case "-p", "--password":
 		return options.Boolean
 	case "-P", "--password-with":
 		return options.Required
+	case "--first-char-class", "--last-char-class":
+		return options.Required
+	case "--insert":
+		return options.Required
+	case "--printable-only":
+		return options.Boolean
+	case "--exclude-homoglyphs":
+		return options.Boolean
+	case "--ensure-printable-ascii":
+		return options.Boolean
+	case "--allowed-chars-report":
+		return options.Boolean
+	case "--min-classes":
+		return options.Required
+	case "--min-unique-chars":
+		return options.Required
+	case "--min-digits":
+		return options.Required
+	case "--min-symbols":
+		return options.Required
+	case "--reject-sequential":
+		return options.Boolean
+	case "--retry-limit":
+		return options.Required
+	case "--shuffle":
+		return options.Boolean
+	case "--weighted":
+		return options.Boolean
+	case "--assert-no-dupes-in-charset":
+		return options.Boolean
+	case "--hyphenate-every", "--hyphenate-with":
+		return options.Required
+	case "--hash":
+		return options.Required
+	case "--wrap":
+		return options.Required
+	case "--output-encoding":
+		return options.Required
+	case "--pepper":
+		return options.Required
+	case "--split":
+		return options.Required
+	case "--entropy-source":
+		return options.Required
+	case "--test-seed":
+		return options.Required
+	case "--i-know-this-is-insecure":
+		return options.Boolean
+	case "--derive":
+		return options.Boolean
+	case "--site", "--master":
+		return options.Required
+	case "--emoji":
+		return options.Boolean
+	case "--alphabet-file", "--charset-spec":
+		return options.Required
 	case "-x", "--hex":
 		return options.Boolean
 	case "-u", "--base64":
 		return options.Boolean
+	case "--config":
+		return options.Required
+	case "--histogram", "--sample":
+		return options.Required
+	case "--mnemonic-checksum":
+		return options.Boolean
+	case "--wordlist-check":
+		return options.Boolean
+	case "--wordlist-stats-json":
+		return options.Boolean
+	case "--debug-charset":
+		return options.Boolean
+	case "--interactive":
+		return options.Boolean
+	case "--list-variants":
+		return options.Boolean
+	case "--benchmark":
+		return options.Required
 	case "-h", "--help":
 		return options.Boolean
 	case "--version":
@@ -113,25 +760,111 @@ func (c *Command) Kind(name string) options.Kind {
 }
 
 func (c *Command) Option(name string, value string, hasValue bool) error {
+	c.given = append(c.given, name)
 	switch name {
 	case "-e", "--show-bits":
 		c.ShowBits = true
+	case "--estimate":
+		c.ShowBits = true
+		c.Estimate = true
+	case "--bits-summary":
+		c.ShowBits = true
+		c.BitsSummary = true
+	case "--guess-rate":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		} else if !(n > 0) || math.IsInf(n, 0) {
+			return strconv.ErrRange
+		}
+		c.GuessRate = n
+	case "--bits-base":
+		switch value {
+		case "2", "e", "10":
+			c.BitsBase = value
+		default:
+			return errors.New("possible values are '2', 'e', '10'")
+		}
+	case "--no-color":
+		colorterm.Enabled = false
+	case "--preview":
+		c.Preview = true
+	case "--show-entropy-bytes":
+		c.ShowEntropyBytes = true
+	case "-q", "--quiet":
+		c.Quiet = true
+	case "-v", "--verbose":
+		c.Verbose = true
 	case "-c", "--count":
 		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
 		if err != nil {
 			return err
-		} else if n == 0 {
-			return strconv.ErrRange
 		}
 		c.Count = uint(n)
+	case "--unique":
+		c.Unique = true
+	case "--progress":
+		c.Progress = true
+	case "--delimiter-between-results":
+		c.ResultDelimiter = &value
+	case "--count-from":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		}
+		c.Numbered = true
+		c.CountFrom = uint(n)
+	case "--also":
+		spec, err := parseAlsoSpec(value)
+		if err != nil {
+			return err
+		}
+		c.Also = append(c.Also, spec)
 	case "-b", "--bits":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		} else if !(n > 0) || math.IsInf(n, 0) {
+			return strconv.ErrRange
+		}
+		c.Bits = n
+	case "--default-bits-passphrase", "--default-bits-password", "--default-bits-hex", "--default-bits-base64":
 		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
 		if err != nil {
 			return err
 		} else if n == 0 {
 			return strconv.ErrRange
 		}
-		c.Bits = uint(n)
+		switch name {
+		case "--default-bits-passphrase":
+			c.DefaultBits[Passphrase] = uint(n)
+		case "--default-bits-password":
+			c.DefaultBits[Password] = uint(n)
+		case "--default-bits-hex":
+			c.DefaultBits[Hexadecimal] = uint(n)
+		case "--default-bits-base64":
+			c.DefaultBits[Base64] = uint(n)
+		}
+	case "--strength-preset":
+		switch value {
+		case "weak":
+			c.StrengthPreset = 64
+		case "standard":
+			c.StrengthPreset = 80
+		case "strong":
+			c.StrengthPreset = 112
+		case "paranoid":
+			c.StrengthPreset = 160
+		default:
+			return errors.New("possible values are 'weak', 'standard', 'strong', 'paranoid'")
+		}
+	case "--length-bits":
+		switch value {
+		case "max", "length", "bits":
+			c.LengthBits = value
+		default:
+			return errors.New("possible values are 'max', 'length', 'bits'")
+		}
 	case "-l", "--length":
 		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
 		if err != nil {
@@ -140,173 +873,2061 @@ func (c *Command) Option(name string, value string, hasValue bool) error {
 			return strconv.ErrRange
 		}
 		c.Length = uint(n)
+	case "--max-bytes":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.MaxBytes = uint(n)
+	case "--passphrase-min-word-len-in-output":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.MinWordLen = uint(n)
+	case "--passphrase-digits":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		}
+		c.Digits = uint(n)
+	case "--passphrase-length-chars":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.LengthChars = uint(n)
+	case "--append-from":
+		set, err := runeset.Parse(value)
+		if err != nil {
+			return err
+		}
+		if set.Picker().Size() == 0 {
+			return errors.New("must contain at least 1 character")
+		}
+		c.AppendChars = &set
+	case "--append-count":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.AppendCount = uint(n)
 	case "-w", "--wordlist":
 		c.Variant = Passphrase
 		c.Wordlist = value
+		c.Pattern = nil
+		c.SLIP39Share = false
+	case "--wordlist-cache":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		} else if d <= 0 {
+			return strconv.ErrRange
+		}
+		c.WordlistCacheTTL = d
+	case "--no-cache":
+		c.NoCache = true
+	case "--refresh":
+		c.RefreshCache = true
+	case "--wordlist-format":
+		switch value {
+		case "plain", "tsv":
+			c.WordlistFormat = value
+		default:
+			return errors.New("possible values are 'plain', 'tsv'")
+		}
+	case "-s", "--separator":
+		c.Separator = value
+	case "--words-only":
+		c.WordsOnly = true
+	case "--passphrase-acrostic":
+		c.Acrostic = true
+	case "--case":
+		switch value {
+		case "lower":
+			c.Case = CaseLower
+		case "upper":
+			c.Case = CaseUpper
+		case "mixed":
+			c.Case = CaseMixed
+		default:
+			return errors.New("possible values are 'lower', 'upper', 'mixed'")
+		}
+	case "--randomize-case":
+		c.Case = CaseMixed
+	case "--locale":
+		tag, err := language.Parse(value)
+		if err != nil {
+			return err
+		}
+		c.Locale = tag
+	case "--slip39-share":
+		c.Variant = Passphrase
+		c.Wordlist = "slip39"
+		c.Pattern, c.Template, c.Sentence = nil, nil, false
+		c.SLIP39Share = true
+	case "--wordlist-column":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.WordlistColumn = uint(n)
+	case "--wordlist-sep":
+		if value == "" {
+			return errors.New("separator must not be empty")
+		}
+		c.WordlistSep = value
+		if c.WordlistColumn == 0 {
+			c.WordlistColumn = 1
+		}
+	case "--allow-empty-wordlist-lines":
+		c.AllowEmptyLines = true
+	case "--trim-wordlist-whitespace":
+		switch value {
+		case "both", "trailing", "none":
+			c.TrimWhitespace = value
+		default:
+			return errors.New("possible values are 'both', 'trailing', 'none'")
+		}
+	case "--no-ambiguous-words":
+		c.NoAmbiguousWords = true
+	case "--ambiguous-words-file":
+		c.NoAmbiguousWords = true
+		c.AmbiguousWordsFile = value
+	case "--pattern":
+		c.Variant = Passphrase
+		c.Pattern = strings.Split(value, ",")
+		c.SLIP39Share, c.Template, c.Sentence = false, nil, false
+		for i, name := range c.Pattern {
+			switch name {
+			case "adj":
+				c.Pattern[i] = "adjectives"
+			case "noun":
+				c.Pattern[i] = "nouns"
+			case "verb":
+				c.Pattern[i] = "verbs"
+			case "":
+				return errors.New("empty wordlist name")
+			}
+		}
+	case "--passphrase-from-sentence":
+		c.Variant = Passphrase
+		c.Pattern, c.SLIP39Share, c.Template = nil, false, nil
+		c.Sentence = true
+	case "--passphrase-template":
+		c.Variant = Passphrase
+		parts, err := parseTemplate(value)
+		if err != nil {
+			return err
+		}
+		c.Pattern, c.SLIP39Share, c.Sentence = nil, false, false
+		c.Template = parts
 	case "-p", "--password":
 		c.Variant = Password
 		set, err := runeset.Parse(`\g`)
 		if err != nil {
 			return err
 		}
-		picker := set.Picker()
-		if picker.Size() < 2 {
+		if set.Picker().Size() < 2 {
 			return errors.New("must contain at least 2 characters")
 		}
-		c.Picker = picker
+		c.Charset = &set
+		c.CharsetSpec = `\g`
 	case "-P", "--password-with":
 		c.Variant = Password
 		set, err := runeset.Parse(value)
 		if err != nil {
 			return err
 		}
-		picker := set.Picker()
-		if picker.Size() < 2 {
+		if set.Picker().Size() < 2 {
 			return errors.New("must contain at least 2 characters")
 		}
-		c.Picker = picker
-	case "-x", "--hex":
-		c.Variant = Hexadecimal
-	case "-u", "--base64":
-		c.Variant = Base64
-	case "-h", "--help":
-		return options.ErrHelp
-	case "--version":
-		return options.ErrVersion
-	default:
-		return options.ErrUnknown
-	}
-	return nil
-}
-
-func (c *Command) getWordlist() ([]string, error) {
-	switch c.Wordlist {
-	case "eff-large":
-		return wordlists.EFFLarge, nil
-	case "eff-short1":
-		return wordlists.EFFShort1, nil
-	case "eff-short2":
-		return wordlists.EFFShort2, nil
-	case "bip39":
-		return wordlists.BIP39, nil
-	case "slip39":
-		return wordlists.SLIP39, nil
-	}
-
-	var r io.Reader = os.Stdin
-	if c.Wordlist != "-" {
-		f, err := os.Open(c.Wordlist)
+		c.Charset = &set
+		c.CharsetSpec = value
+	case "--emoji":
+		c.Variant = Password
+		var set runeset.RuneSet
+		set.AddRangeTable(emojiTable)
+		set.MergeAdjacents()
+		c.Charset = &set
+		c.CharsetSpec = ""
+	case "--alphabet-file":
+		c.Variant = Password
+		set, err := readAlphabet(value)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		defer f.Close()
-		r = f
-	}
-
-	var wordlist []string
-
-	scanner := bufio.NewScanner(r)
-	scanner.Split(bufio.ScanLines)
-	for scanner.Scan() {
-		wordlist = append(wordlist, scanner.Text())
-	}
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
-
-	if len(wordlist) < 2 {
-		return nil, errors.New("wordlist must contain at least 2 words")
-	}
-
-	return wordlist, nil
-}
-
-func (c *Command) getNumOfElems(bitsPerElem float64, defaultBits uint) uint {
-	switch {
-	case c.Length != 0:
-		return c.Length
-	case c.Bits != 0:
-		return uint(math.Ceil(float64(c.Bits) / bitsPerElem))
-	default:
-		return uint(math.Ceil(float64(defaultBits) / bitsPerElem))
-	}
-}
-
-func (c *Command) getGenerator() (Generator, float64, error) {
-	switch c.Variant {
-	case Passphrase:
-		wordlist, err := c.getWordlist()
+		c.Charset = &set
+		c.CharsetSpec = ""
+	case "--charset-spec":
+		c.Variant = Password
+		set, err := readCharsetSpec(value)
 		if err != nil {
-			return nil, 0, err
+			return err
 		}
-		bitsPerElem := math.Log2(float64(len(wordlist)))
-		nwords := c.getNumOfElems(bitsPerElem, 80)
-		return newPassphraseGenerator(wordlist, nwords), bitsPerElem * float64(nwords), nil
-	case Password:
-		if c.Picker == nil {
-			panic("genpass: c.Picker is nil")
-		}
-		bitsPerElem := math.Log2(float64(c.Picker.Size()))
-		nchars := c.getNumOfElems(bitsPerElem, 80)
-		return newPasswordGenerator(c.Picker, nchars), bitsPerElem * float64(nchars), nil
-	case Hexadecimal:
-		bitsPerElem := float64(4)
-		nchars := c.getNumOfElems(bitsPerElem, 128)
-		return newHexGenerator(nchars), bitsPerElem * float64(nchars), nil
-	case Base64:
-		bitsPerElem := float64(6)
-		nchars := c.getNumOfElems(bitsPerElem, 128)
-		return newBase64Generator(nchars), bitsPerElem * float64(nchars), nil
-	default:
-		panic("genpass: invalid Variant")
-	}
-}
-
-func run(args []string) error {
-	c := &Command{
-		Count:    1,
-		Variant:  Passphrase,
-		Wordlist: "eff-large",
-	}
-
-	switch _, err := options.Parse(c, args); {
+		c.Charset = &set
+		c.CharsetSpec = ""
+	case "--first-char-class":
+		set, err := runeset.Parse(value)
+		if err != nil {
+			return err
+		}
+		if set.Picker().Size() == 0 {
+			return errors.New("must contain at least 1 character")
+		}
+		c.FirstChars = &set
+	case "--last-char-class":
+		set, err := runeset.Parse(value)
+		if err != nil {
+			return err
+		}
+		if set.Picker().Size() == 0 {
+			return errors.New("must contain at least 1 character")
+		}
+		c.LastChars = &set
+	case "--insert":
+		spec, err := parseInsertSpec(value)
+		if err != nil {
+			return err
+		}
+		for _, other := range c.Inserts {
+			if other.Pos == spec.Pos {
+				return fmt.Errorf("position %d is given more than once", spec.Pos)
+			}
+		}
+		c.Inserts = append(c.Inserts, spec)
+	case "--printable-only":
+		c.PrintableOnly = true
+	case "--exclude-homoglyphs":
+		c.ExcludeHomoglyphs = true
+	case "--ensure-printable-ascii":
+		c.EnsurePrintableASCII = true
+	case "--allowed-chars-report":
+		c.AllowedCharsReport = true
+	case "--shuffle":
+		c.Shuffle = true
+	case "--weighted":
+		c.Weighted = true
+	case "--assert-no-dupes-in-charset":
+		c.AssertNoDupes = true
+	case "--retry-limit":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.RetryLimit = uint(n)
+	case "--min-classes":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 || n > numCharClasses {
+			return strconv.ErrRange
+		}
+		c.MinClasses = uint(n)
+	case "--min-unique-chars":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.MinUniqueChars = uint(n)
+	case "--min-digits":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.MinDigits = uint(n)
+	case "--min-symbols":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.MinSymbols = uint(n)
+	case "--reject-sequential":
+		c.RejectSequential = true
+	case "--hyphenate-every":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.HyphenateEvery = uint(n)
+	case "--hash":
+		switch value {
+		case "bcrypt", "argon2id":
+			c.Hash = value
+		default:
+			return errors.New("possible values are 'bcrypt', 'argon2id'")
+		}
+	case "--output-encoding":
+		switch value {
+		case "utf8":
+			c.EscapeOutput = false
+		case "ascii-escaped":
+			c.EscapeOutput = true
+		default:
+			return errors.New("possible values are 'utf8', 'ascii-escaped'")
+		}
+	case "--wrap":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.Wrap = uint(n)
+	case "--hyphenate-with":
+		c.HyphenateWith = value
+	case "--pepper":
+		if value == "" {
+			return errors.New("must not be empty")
+		}
+		c.Pepper = []byte(value)
+	case "--split":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n < 2 {
+			return strconv.ErrRange
+		}
+		c.Split = uint(n)
+	case "--entropy-source":
+		path, ok := strings.CutPrefix(value, "file:")
+		if !ok || path == "" {
+			return errors.New("must be in the form file:PATH")
+		}
+		c.EntropySource = path
+	case "--test-seed":
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		c.TestSeed = &n
+	case "--i-know-this-is-insecure":
+		c.Insecure = true
+	case "--derive":
+		c.Derive = true
+	case "--site":
+		c.Site = value
+	case "--master":
+		if value == "" {
+			return errors.New("must not be empty")
+		}
+		c.Master = value
+	case "-x", "--hex":
+		c.Variant = Hexadecimal
+	case "-u", "--base64":
+		c.Variant = Base64
+	case "--config":
+	case "--histogram":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.Histogram = uint(n)
+	case "--sample":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.Sample = uint(n)
+	case "--mnemonic-checksum":
+		c.MnemonicChecksum = true
+	case "--wordlist-check":
+		c.WordlistCheck = true
+	case "--wordlist-stats-json":
+		c.WordlistStatsJSON = true
+	case "--debug-charset":
+		c.DebugCharset = true
+	case "--interactive":
+		c.Interactive = true
+	case "--list-variants":
+		c.ListVariants = true
+	case "--benchmark":
+		n, err := strconv.ParseUint(value, 10, strconv.IntSize)
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return strconv.ErrRange
+		}
+		c.Benchmark = uint(n)
+	case "-h", "--help":
+		return options.ErrHelp
+	case "--version":
+		return options.ErrVersion
+	default:
+		return options.ErrUnknown
+	}
+	return nil
+}
+
+var envOptions = []struct {
+	env string
+	key string
+}{
+	{"GENPASS_WORDLIST", "wordlist"},
+	{"GENPASS_BITS", "bits"},
+	{"GENPASS_SEPARATOR", "separator"},
+}
+
+func (c *Command) loadEnv() error {
+	for _, opt := range envOptions {
+		value := os.Getenv(opt.env)
+		if value == "" {
+			continue
+		}
+		if err := c.configOption(opt.key, value); err != nil {
+			return options.Errorf("%s: %w", opt.env, err)
+		}
+	}
+	return nil
+}
+
+func (c *Command) getWordlist(name string) ([]string, []uint64, error) {
+	switch name {
+	case "eff-large":
+		return wordlists.EFFLarge, nil, nil
+	case "eff-short1":
+		return wordlists.EFFShort1, nil, nil
+	case "eff-short2":
+		return wordlists.EFFShort2, nil, nil
+	case "bip39":
+		return wordlists.BIP39, nil, nil
+	case "slip39":
+		return wordlists.SLIP39, nil, nil
+	case "adjectives":
+		return wordlists.Adjectives, nil, nil
+	case "nouns":
+		return wordlists.Nouns, nil, nil
+	case "verbs":
+		return wordlists.Verbs, nil, nil
+	}
+
+	r := c.Stdin
+	if command, ok := strings.CutPrefix(name, "exec:"); ok {
+		data, err := c.readCommandOutputCached(command)
+		if err != nil {
+			return nil, nil, err
+		}
+		r = bytes.NewReader(data)
+	} else if name != "-" {
+		f, err := os.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			if suggestion, ok := suggestWordlist(name); ok {
+				return nil, nil, options.Errorf("unknown wordlist %q (did you mean %q?)", name, suggestion)
+			}
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	trim := strings.TrimSpace
+	switch c.TrimWhitespace {
+	case "trailing":
+		trim = func(s string) string {
+			return strings.TrimRightFunc(s, unicode.IsSpace)
+		}
+	case "none":
+		trim = func(s string) string {
+			return s
+		}
+	}
+
+	var wordlist []string
+	var weights []uint64
+	var total uint64
+
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanLines)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := scanner.Text()
+		if !c.AllowEmptyLines && strings.TrimSpace(line) == "" {
+			continue
+		}
+		if c.WordlistFormat != "tsv" {
+			if c.WordlistColumn != 0 {
+				var fields []string
+				if c.WordlistSep != "" {
+					fields = strings.Split(line, c.WordlistSep)
+				} else {
+					fields = strings.Fields(line)
+				}
+				if uint(len(fields)) < c.WordlistColumn {
+					return nil, nil, fmt.Errorf("wordlist: line %d: missing column %d", lineno, c.WordlistColumn)
+				}
+				line = fields[c.WordlistColumn-1]
+			}
+			wordlist = append(wordlist, trim(line))
+			continue
+		}
+		word, freq, ok := strings.Cut(line, "\t")
+		if !ok {
+			return nil, nil, fmt.Errorf("wordlist: line %d: missing frequency column", lineno)
+		}
+		word = trim(word)
+		weight, err := strconv.ParseUint(strings.TrimSpace(freq), 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("wordlist: line %d: invalid frequency: %w", lineno, err)
+		}
+		if weight == 0 {
+			continue
+		}
+		if total+weight < total {
+			return nil, nil, errors.New("wordlist: total frequency is too large")
+		}
+		total += weight
+		wordlist = append(wordlist, word)
+		weights = append(weights, weight)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(wordlist) < 2 {
+		return nil, nil, errors.New("wordlist must contain at least 2 words")
+	}
+
+	return wordlist, weights, nil
+}
+
+func prefixPairs(words []string) [][2]string {
+	sorted := slices.Clone(words)
+	slices.Sort(sorted)
+
+	var pairs [][2]string
+	for i, word := range sorted {
+		for _, other := range sorted[i+1:] {
+			if !strings.HasPrefix(other, word) {
+				break
+			}
+			pairs = append(pairs, [2]string{word, other})
+		}
+	}
+	return pairs
+}
+
+func (c *Command) checkWordlist() error {
+	words, _, err := c.getWordlist(c.Wordlist)
+	if err != nil {
+		return err
+	}
+	pairs := prefixPairs(words)
+	for _, pair := range pairs {
+		fmt.Fprintf(c.Stdout, "%q is a prefix of %q\n", pair[0], pair[1])
+	}
+	if len(pairs) != 0 {
+		return fmt.Errorf("wordlist is not prefix-free (%d pairs)", len(pairs))
+	}
+	fmt.Fprintln(c.Stdout, "wordlist is prefix-free")
+	return nil
+}
+
+func shannonEntropy(weights []uint64) float64 {
+	var total float64
+	for _, weight := range weights {
+		total += float64(weight)
+	}
+	var entropy float64
+	for _, weight := range weights {
+		if weight != 0 {
+			p := float64(weight) / total
+			entropy -= p * math.Log2(p)
+		}
+	}
+	return entropy
+}
+
+// isGiven reports whether any of names was given on the command line.
+func (c *Command) isGiven(names ...string) bool {
+	return slices.ContainsFunc(c.given, func(name string) bool {
+		return slices.Contains(names, name)
+	})
+}
+
+func (c *Command) applyStrengthPreset() {
+	if c.StrengthPreset != 0 && !c.isGiven("-b", "--bits", "-l", "--length") {
+		c.Bits = float64(c.StrengthPreset)
+	}
+}
+
+func (c *Command) defaultBits(variant Variant) uint {
+	if n := c.DefaultBits[variant]; n != 0 {
+		return n
+	}
+	return variants[variant].DefaultBits
+}
+
+func (c *Command) retryLimit() uint {
+	if c.RetryLimit != 0 {
+		return c.RetryLimit
+	}
+	return defaultRetryLimit
+}
+
+func (c *Command) appendCount() uint {
+	if c.AppendCount != 0 {
+		return c.AppendCount
+	}
+	return 1
+}
+
+func (c *Command) lengthAndTarget(defaultBits uint) (uint, float64) {
+	// --length-bits combines the two only when both are given on the
+	// command line; otherwise the one given there wins over the other from
+	// the environment or the configuration file.
+	lengthGiven, bitsGiven := c.isGiven("-l", "--length"), c.isGiven("-b", "--bits")
+	switch {
+	case c.Length == 0 && c.Bits == 0:
+		return 0, float64(defaultBits)
+	case c.Length == 0:
+		return 0, c.Bits
+	case c.Bits == 0 || c.LengthBits == "length" || lengthGiven && !bitsGiven:
+		return c.Length, 0
+	case c.LengthBits == "bits" || bitsGiven && !lengthGiven:
+		return 0, c.Bits
+	default:
+		return c.Length, c.Bits
+	}
+}
+
+func (c *Command) getNumOfElems(bitsPerElem float64, defaultBits uint) uint {
+	length, target := c.lengthAndTarget(defaultBits)
+	return max(length, uint(math.Ceil(target/bitsPerElem)))
+}
+
+func digitsBits(nwords, ndigits uint) float64 {
+	if ndigits == 0 {
+		return 0
+	}
+	n, k := float64(nwords+ndigits), float64(ndigits)
+	lgn, _ := math.Lgamma(n + 1)
+	lgk, _ := math.Lgamma(k + 1)
+	lgnk, _ := math.Lgamma(n - k + 1)
+	return k*math.Log2(10) + (lgn-lgk-lgnk)/math.Ln2
+}
+
+func (c *Command) getNumOfFits(elemSize, sepSize uint) (uint, error) {
+	fits := (c.MaxBytes + sepSize) / (elemSize + sepSize)
+	if fits == 0 {
+		return 0, fmt.Errorf("%w: --max-bytes must be at least %d", ErrConstraints, elemSize)
+	}
+	return fits, nil
+}
+
+// warnMaxBytes warns when --max-bytes has shortened the output below the
+// default strength. An explicit --bits is checked by run.
+func (c *Command) warnMaxBytes(bits, target float64) {
+	if c.Bits == 0 && bits < target {
+		c.Warnf("--max-bytes: generated strings have only %.2f bits of strength, less than the default of %.0f bits", bits, target)
+	}
+}
+
+func filterShortWords(words []string, weights []uint64, minLen uint) ([]string, []uint64) {
+	var kept []string
+	var keptWeights []uint64
+	for i, word := range words {
+		if uint(utf8.RuneCountInString(word)) < minLen {
+			continue
+		}
+		kept = append(kept, word)
+		if weights != nil {
+			keptWeights = append(keptWeights, weights[i])
+		}
+	}
+	return kept, keptWeights
+}
+
+func (c *Command) filterShortWords(name string, words []string, weights []uint64) ([]string, []uint64, error) {
+	if c.MinWordLen == 0 {
+		return words, weights, nil
+	}
+	n := len(words)
+	words, weights = filterShortWords(words, weights, c.MinWordLen)
+	if len(words) < 2 {
+		return nil, nil, fmt.Errorf("%w: %v: too few words of at least %d characters", ErrConstraints, name, c.MinWordLen)
+	}
+	c.Debugf("removed %d words shorter than %d characters", n-len(words), c.MinWordLen)
+	return words, weights, nil
+}
+
+// filterLongWords removes the words that do not fit in --max-bytes even on
+// their own, so that the passphrase is sized by the words that do.
+func (c *Command) filterLongWords(name string, words []string, weights []uint64) ([]string, []uint64, error) {
+	var kept []string
+	var keptWeights []uint64
+	for i, word := range words {
+		size := uint(len(word))
+		if c.Case == CaseMixed {
+			size, _, _ = mixedCaseBounds(word, c.Locale)
+		}
+		if size > c.MaxBytes {
+			continue
+		}
+		kept = append(kept, word)
+		if weights != nil {
+			keptWeights = append(keptWeights, weights[i])
+		}
+	}
+	if len(kept) < 2 {
+		return nil, nil, fmt.Errorf("%w: %v: too few words of at most %d bytes", ErrConstraints, name, c.MaxBytes)
+	}
+	c.Debugf("removed %d words longer than %d bytes", len(words)-len(kept), c.MaxBytes)
+	return kept, keptWeights, nil
+}
+
+// caseWords applies wcase to words ahead of generation and merges the words
+// that become equal, adding up their weights. For CaseMixed, the words are
+// folded to lower case, from which randomizeCase picks the case of each
+// letter.
+func caseWords(words []string, weights []uint64, wcase Case, tag language.Tag) ([]string, []uint64) {
+	if wcase == CaseMixed {
+		wcase = CaseLower
+	}
+	index := make(map[string]int, len(words))
+	var kept []string
+	var keptWeights []uint64
+	for i, word := range words {
+		word = wcase.apply(word, tag)
+		if j, ok := index[word]; ok {
+			if weights != nil {
+				keptWeights[j] += weights[i]
+			}
+			continue
+		}
+		index[word] = len(kept)
+		kept = append(kept, word)
+		if weights != nil {
+			keptWeights = append(keptWeights, weights[i])
+		}
+	}
+	return kept, keptWeights
+}
+
+func (c *Command) caseWords(name string, words []string, weights []uint64) ([]string, []uint64, error) {
+	n := len(words)
+	words, weights = caseWords(words, weights, c.Case, c.Locale)
+	if len(words) < 2 {
+		return nil, nil, fmt.Errorf("%v: too few words remain after changing their case", name)
+	}
+	if n != len(words) {
+		c.Debugf("merged %d words that differ only in case", n-len(words))
+	}
+	return words, weights, nil
+}
+
+func casedLetters(word string) uint {
+	var n uint
+	for _, r := range word {
+		if unicode.ToUpper(r) != unicode.ToLower(r) {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *Command) newPassphraseGenerator(lists []*Wordlist, nwords, ndigits, maxChars uint) Generator {
+	return newPassphraseGenerator(lists, nwords, ndigits, maxChars, c.Separator, c.Case, c.Locale)
+}
+
+// passphraseAcrostic returns the initials of the words of passphrase,
+// skipping the digits of --passphrase-digits.
+func passphraseAcrostic(passphrase, sep string, digits bool) string {
+	words := strings.Split(passphrase, sep)
+	if digits {
+		words = slices.DeleteFunc(words, func(word string) bool {
+			return len(word) == 1 && '0' <= word[0] && word[0] <= '9'
+		})
+	}
+	return acrostic(words)
+}
+
+func (c *Command) appendFromCharset(generator Generator, bits float64) (Generator, float64) {
+	if c.AppendChars == nil {
+		return generator, bits
+	}
+	picker := c.AppendChars.Picker()
+	c.Debugf("characters appended to passphrases: %d (charset size: %d)", c.appendCount(), picker.Size())
+	return newAppendGenerator(generator, picker, c.appendCount()), bits + float64(c.appendCount())*picker.Bits()
+}
+
+func (c *Command) getSLIP39ShareGenerator(defaultBits uint) (Generator, float64, error) {
+	if len(c.Pattern) != 0 || c.Digits != 0 || c.Case != CaseLower || c.Acrostic || c.AppendChars != nil {
+		return nil, 0, options.Errorf("--slip39-share cannot be used with --pattern, --passphrase-digits, --case, --passphrase-acrostic or --append-from")
+	}
+
+	length, bits := c.lengthAndTarget(defaultBits)
+	secretBytes := 16
+	switch length {
+	case 0, 20:
+	case 33:
+		secretBytes = 32
+	default:
+		return nil, 0, errors.New("SLIP39 shares must consist of 20 or 33 words")
+	}
+	switch {
+	case bits > 256:
+		return nil, 0, errors.New("SLIP39 shares can encode at most 256 bits")
+	case bits > 128:
+		secretBytes = 32
+	}
+	return newSLIP39ShareGenerator(secretBytes, c.Separator), float64(8 * secretBytes), nil
+}
+
+func (c *Command) ambiguousWords() (map[string]bool, error) {
+	if c.AmbiguousWordsFile == "" {
+		return nil, nil
+	}
+	return readExcludeList(c.AmbiguousWordsFile)
+}
+
+// getPassphraseWords reads the wordlist name and applies
+// --no-ambiguous-words, --case and --passphrase-min-word-len-in-output to it.
+func (c *Command) getPassphraseWords(name string, exclude map[string]bool) ([]string, []uint64, error) {
+	words, weights, err := c.getWordlist(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.NoAmbiguousWords {
+		n := len(words)
+		words, weights = filterAmbiguousWords(words, weights, exclude)
+		if len(words) < 2 {
+			return nil, nil, fmt.Errorf("%v: too few words remain after removing ambiguous words", name)
+		}
+		c.Debugf("removed %d ambiguous words", n-len(words))
+	}
+	if words, weights, err = c.caseWords(name, words, weights); err != nil {
+		return nil, nil, err
+	}
+	return c.filterShortWords(name, words, weights)
+}
+
+func (c *Command) getPassphraseGenerator(defaultBits uint) (Generator, float64, error) {
+	if c.Template != nil {
+		return c.getTemplateGenerator(defaultBits)
+	}
+	if c.Sentence {
+		return c.getSentenceGenerator(defaultBits)
+	}
+	if c.SLIP39Share {
+		return c.getSLIP39ShareGenerator(defaultBits)
+	}
+	if c.Acrostic && c.MaxBytes != 0 {
+		return nil, 0, options.Errorf("--passphrase-acrostic cannot be used with --max-bytes")
+	}
+	if c.Acrostic {
+		if c.Separator == "" {
+			return nil, 0, options.Errorf("--passphrase-acrostic cannot be used with an empty --separator")
+		}
+		sep, digits := c.Separator, c.Digits != 0
+		c.acrostic = func(passphrase string) string {
+			return passphraseAcrostic(passphrase, sep, digits)
+		}
+	}
+	if c.AppendChars != nil && c.MaxBytes != 0 {
+		return nil, 0, options.Errorf("--append-from cannot be used with --max-bytes")
+	}
+	if c.Separator == "" && c.MaxBytes != 0 {
+		return nil, 0, options.Errorf("--max-bytes cannot be used with an empty --separator")
+	}
+	if c.AppendCount != 0 && c.AppendChars == nil {
+		return nil, 0, options.Errorf("--append-count requires --append-from")
+	}
+
+	names := c.Pattern
+	if len(names) == 0 {
+		names = []string{c.Wordlist}
+	}
+
+	exclude, err := c.ambiguousWords()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lists := make([]*Wordlist, len(names))
+	bitsPerElem := make([]float64, len(names))
+	var maxWordLen uint
+	maxChars := make([]uint, len(names))
+	minChars := make([]uint, len(names))
+	for i, name := range names {
+		if j := slices.Index(names[:i], name); j >= 0 {
+			lists[i], bitsPerElem[i] = lists[j], bitsPerElem[j]
+			maxChars[i], minChars[i] = maxChars[j], minChars[j]
+			continue
+		}
+		words, weights, err := c.getPassphraseWords(name, exclude)
+		if err != nil {
+			return nil, 0, err
+		}
+		if c.MaxBytes != 0 {
+			if words, weights, err = c.filterLongWords(name, words, weights); err != nil {
+				return nil, 0, err
+			}
+		}
+		lists[i] = newWordlist(words, weights)
+		if weights != nil {
+			bitsPerElem[i] = shannonEntropy(weights)
+		} else {
+			bitsPerElem[i] = math.Log2(float64(len(words)))
+		}
+		minCased := uint(math.MaxUint)
+		minChars[i] = math.MaxUint
+		for _, word := range words {
+			size, runes := uint(len(word)), uint(utf8.RuneCountInString(word))
+			minRunes, maxRunes := runes, runes
+			if c.Case == CaseMixed {
+				size, minRunes, maxRunes = mixedCaseBounds(word, c.Locale)
+			}
+			maxWordLen = max(maxWordLen, size)
+			maxChars[i] = max(maxChars[i], maxRunes)
+			minChars[i] = min(minChars[i], minRunes)
+			minCased = min(minCased, casedLetters(word))
+		}
+		if c.Case == CaseMixed {
+			bitsPerElem[i] += float64(minCased)
+		}
+		c.Debugf("wordlist size: %d", len(words))
+	}
+	wordsBits := func(nwords uint) float64 {
+		var bits float64
+		for i := range nwords {
+			bits += bitsPerElem[i%uint(len(bitsPerElem))]
+		}
+		return bits
+	}
+
+	if c.LengthChars != 0 {
+		if c.Length != 0 || c.Digits != 0 {
+			return nil, 0, options.Errorf("--passphrase-length-chars cannot be used with --length or --passphrase-digits")
+		}
+		lengthChars := c.LengthChars
+		if c.AppendChars != nil {
+			if c.appendCount() >= lengthChars {
+				return nil, 0, fmt.Errorf("%w: --passphrase-length-chars must be greater than --append-count", ErrConstraints)
+			}
+			lengthChars -= c.appendCount()
+		}
+		if minChars[0] > lengthChars {
+			return nil, 0, fmt.Errorf("%w: --passphrase-length-chars must be at least %d", ErrConstraints, minChars[0])
+		}
+		sepChars := uint(utf8.RuneCountInString(c.Separator))
+		var nwords, length uint
+		for {
+			n := maxChars[nwords%uint(len(maxChars))]
+			if nwords != 0 {
+				n += sepChars
+			}
+			if length+n > lengthChars {
+				break
+			}
+			length += n
+			nwords++
+		}
+		c.Debugf("words always fitting in %d characters: %d", lengthChars, nwords)
+		if nwords == 0 {
+			c.Warnf("not every word fits in %d characters; longer words are skipped and strength is reported as 0 bits", lengthChars)
+		}
+
+		generator := c.newPassphraseGenerator(lists, 0, 0, lengthChars)
+		if c.MaxBytes != 0 {
+			fits, err := c.getNumOfFits(maxWordLen, uint(len(c.Separator)))
+			if err != nil {
+				return nil, 0, err
+			}
+			generator = newMaxBytesGenerator(generator, c.MaxBytes, c.Separator)
+			nwords = min(nwords, fits)
+		}
+		generator, bits := c.appendFromCharset(generator, wordsBits(nwords))
+		return generator, bits, nil
+	}
+
+	nwords, target := c.lengthAndTarget(defaultBits)
+	for wordsBits(nwords) < target {
+		nwords++
+	}
+	ndigits := c.Digits
+	if c.MaxBytes != 0 {
+		// Shorten the passphrase before generating it, rather than trimming
+		// the result, so that the strength counts exactly the words and
+		// digits in the output. Digits are dropped before words.
+		elemSize := maxWordLen
+		if ndigits != 0 {
+			elemSize = max(elemSize, 1)
+		}
+		fits, err := c.getNumOfFits(elemSize, uint(len(c.Separator)))
+		if err != nil {
+			return nil, 0, err
+		}
+		nwords = min(nwords, fits)
+		ndigits = min(ndigits, fits-nwords)
+	}
+	c.Debugf("words per passphrase: %d", nwords)
+
+	generator := c.newPassphraseGenerator(lists, nwords, ndigits, 0)
+	bits := wordsBits(nwords) + digitsBits(nwords, ndigits)
+	if c.MaxBytes != 0 {
+		c.warnMaxBytes(bits, target)
+	}
+	generator, bits = c.appendFromCharset(generator, bits)
+	return generator, bits, nil
+}
+
+func readAlphabet(name string) (runeset.RuneSet, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return runeset.RuneSet{}, err
+	}
+	if !utf8.Valid(data) {
+		return runeset.RuneSet{}, fmt.Errorf("%v: invalid UTF-8", name)
+	}
+
+	var set runeset.RuneSet
+	seen := make(map[rune]bool)
+	for _, r := range string(data) {
+		if r == '\n' || r == '\r' {
+			continue
+		}
+		if seen[r] {
+			return runeset.RuneSet{}, fmt.Errorf("%v: duplicate character %q", name, r)
+		}
+		seen[r] = true
+		set.Add(r)
+	}
+	set.MergeAdjacents()
+	if set.Picker().Size() < 2 {
+		return runeset.RuneSet{}, fmt.Errorf("%v: must contain at least 2 characters", name)
+	}
+	return set, nil
+}
+
+func readCharsetSpec(name string) (runeset.RuneSet, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return runeset.RuneSet{}, err
+	}
+	defer f.Close()
+
+	var set runeset.RuneSet
+	scanner := bufio.NewScanner(f)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := scanner.Text()
+		if lineno == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var apply func(*runeset.RuneSet) runeset.RuneSet
+		var spec string
+		switch {
+		case strings.HasPrefix(line, "+"):
+			apply, spec = set.Union, line[1:]
+		case strings.HasPrefix(line, "-"):
+			apply, spec = set.Subtract, line[1:]
+		case strings.HasPrefix(line, "&"):
+			apply, spec = set.Intersect, line[1:]
+		default:
+			r, _ := utf8.DecodeRuneInString(line)
+			return runeset.RuneSet{}, fmt.Errorf("%v:%d: unknown operator %q (expected +, - or &)", name, lineno, r)
+		}
+		operand, err := runeset.Parse(spec)
+		if err != nil {
+			return runeset.RuneSet{}, fmt.Errorf("%v:%d: %w", name, lineno, err)
+		}
+		set = apply(&operand)
+	}
+	if err := scanner.Err(); err != nil {
+		return runeset.RuneSet{}, err
+	}
+	set.MergeAdjacents()
+	if set.Picker().Size() < 2 {
+		return runeset.RuneSet{}, fmt.Errorf("%v: must contain at least 2 characters", name)
+	}
+	return set, nil
+}
+
+func (c *Command) getCharset() (*runeset.RuneSet, error) {
+	if c.Charset == nil {
+		panic("genpass: c.Charset is nil")
+	}
+
+	charset := c.Charset
+	if c.PrintableOnly {
+		var printable runeset.RuneSet
+		for _, table := range []*unicode.RangeTable{unicode.L, unicode.N, unicode.P, unicode.S} {
+			printable.AddRangeTable(table)
+		}
+		printable.MergeAdjacents()
+		set := charset.Intersect(&printable)
+		if set.Picker().Size() < 2 {
+			return nil, errors.New("the charset must contain at least 2 printable characters")
+		}
+		charset = &set
+	} else {
+		var control runeset.RuneSet
+		control.AddRangeTable(unicode.Cc)
+		if set := charset.Intersect(&control); set.Len() != 0 {
+			c.Warnf("the charset contains control characters")
+		}
+	}
+	if c.ExcludeHomoglyphs {
+		set := excludeHomoglyphs(charset)
+		if set.Picker().Size() < 2 {
+			return nil, errors.New("the charset must contain at least 2 characters other than homoglyphs")
+		}
+		c.Debugf("--exclude-homoglyphs: charset size reduced from %d to %d", charset.Picker().Size(), set.Picker().Size())
+		charset = &set
+	}
+	return charset, nil
+}
+
+func (c *Command) printHistogram() error {
+	if c.Variant != Password || c.Charset == nil {
+		return options.Errorf("--histogram requires -p or -P")
+	}
+	charset, err := c.getCharset()
+	if err != nil {
+		return err
+	}
+	picker := charset.Picker()
+	if picker.Size() > 256 {
+		return options.Errorf("--histogram requires a charset of at most 256 characters")
+	}
+
+	counts := make(map[rune]uint, picker.Size())
+	for range c.Histogram {
+		counts[picker.Get(randomInt64(picker.Size()))]++
+	}
+
+	expected := float64(c.Histogram) / float64(picker.Size())
+	var chi2 float64
+	fmt.Fprintf(c.Stdout, "CHAR\tCOUNT\tRATIO\n")
+	for i := range picker.Size() {
+		r := picker.Get(i)
+		count := float64(counts[r])
+		chi2 += (count - expected) * (count - expected) / expected
+		fmt.Fprintf(c.Stdout, "%q\t%d\t%.4f\n", r, counts[r], count/expected)
+	}
+	fmt.Fprintf(c.Stdout, "chi-squared: %.2f (%d degrees of freedom)\n", chi2, picker.Size()-1)
+	return nil
+}
+
+// wrapLine breaks s into lines of at most cols characters. If escape is
+// true, the characters are escaped as by escapeNonASCII first, and each
+// escape sequence is kept on one line and counted by its length.
+func wrapLine(s string, cols uint, escape bool) string {
+	var b strings.Builder
+	var n uint
+	for _, r := range s {
+		if r == '\n' {
+			b.WriteByte('\n')
+			n = 0
+			continue
+		}
+		unit := string(r)
+		if escape {
+			unit = escapeRune(r)
+		}
+		width := uint(utf8.RuneCountInString(unit))
+		if n != 0 && n+width > cols {
+			b.WriteByte('\n')
+			n = 0
+		}
+		b.WriteString(unit)
+		n += width
+	}
+	return b.String()
+}
+
+func checkPrintableASCII(s string, allowNewline bool) error {
+	for _, r := range s {
+		if (r < ' ' || r > '~') && !(allowNewline && r == '\n') {
+			return fmt.Errorf("generated string contains a character that is not printable ASCII: %q", r)
+		}
+	}
+	return nil
+}
+
+var riskyChars = []struct {
+	Context string
+	Chars   string
+}{
+	{"shells", " !\"#$&'()*;<>?\\`|~"},
+	{"CSV", "\","},
+	{"URLs", " #%&+/:=?@"},
+}
+
+func riskyCharsReport(picker *runeset.Picker) string {
+	var reports []string
+	for _, risky := range riskyChars {
+		var found []string
+		for _, r := range risky.Chars {
+			if picker.Contains(r) {
+				found = append(found, strconv.QuoteRune(r))
+			}
+		}
+		if len(found) != 0 {
+			reports = append(reports, risky.Context+": "+strings.Join(found, " "))
+		}
+	}
+	return strings.Join(reports, "; ")
+}
+
+func escapeRune(r rune) string {
+	switch {
+	case r == '\\':
+		return `\\`
+	case r == '\n' || r >= ' ' && r <= '~':
+		return string(r)
+	case r <= 0xFFFF:
+		return fmt.Sprintf(`\u%04X`, r)
+	default:
+		return fmt.Sprintf(`\U%08X`, r)
+	}
+}
+
+func escapeNonASCII(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		b.WriteString(escapeRune(r))
+	}
+	return b.String()
+}
+
+func formatRanges(set *runeset.RuneSet) string {
+	var b strings.Builder
+	for i, r := range set.Ranges() {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strconv.QuoteRune(r.Lo()))
+		if r.Lo() != r.Hi() {
+			b.WriteByte('-')
+			b.WriteString(strconv.QuoteRune(r.Hi()))
+		}
+	}
+	return b.String()
+}
+
+func (c *Command) printSample(w io.Writer) error {
+	switch c.Variant {
+	case Passphrase:
+		if c.Template != nil || c.Sentence {
+			return options.Errorf("--sample cannot be used with --passphrase-template or --passphrase-from-sentence")
+		}
+		names := c.Pattern
+		if len(names) == 0 {
+			names = []string{c.Wordlist}
+		}
+		exclude, err := c.ambiguousWords()
+		if err != nil {
+			return err
+		}
+		lists := make([]*Wordlist, len(names))
+		for i, name := range names {
+			if j := slices.Index(names[:i], name); j >= 0 {
+				lists[i] = lists[j]
+				continue
+			}
+			words, weights, err := c.getPassphraseWords(name, exclude)
+			if err != nil {
+				return err
+			}
+			lists[i] = newWordlist(words, weights)
+		}
+		for i := range c.Sample {
+			fmt.Fprintln(w, c.Case.apply(lists[i%uint(len(lists))].Random(), c.Locale))
+		}
+	case Password:
+		charset, err := c.getCharset()
+		if err != nil {
+			return err
+		}
+		picker := charset.Picker()
+		for range c.Sample {
+			fmt.Fprintln(w, picker.RandomStringFrom(random, 1))
+		}
+	default:
+		return options.Errorf("--sample requires a passphrase variant, -p or -P")
+	}
+	return nil
+}
+
+func (c *Command) printCharset() error {
+	if c.Variant != Password || c.CharsetSpec == "" {
+		return options.Errorf("--debug-charset requires -p or -P")
+	}
+	raw, err := runeset.ParseRaw(c.CharsetSpec)
+	if err != nil {
+		return err
+	}
+	merged, err := runeset.Parse(c.CharsetSpec)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Stdout, "raw (%d ranges): %s\n", raw.Len(), formatRanges(&raw))
+	fmt.Fprintf(c.Stdout, "merged (%d ranges): %s\n", merged.Len(), formatRanges(&merged))
+	return nil
+}
+
+func (c *Command) printVariants() {
+	fmt.Fprintf(c.Stdout, "VARIANT\tDEFAULT BITS\n")
+	for i, v := range variants {
+		fmt.Fprintf(c.Stdout, "%v\t%d\n", v.Name, c.defaultBits(Variant(i)))
+	}
+}
+
+func parseAlsoSpec(value string) (alsoSpec, error) {
+	name, bitsStr, hasBits := strings.Cut(value, ":")
+	var spec alsoSpec
+	found := false
+	for i, v := range variants {
+		if v.Name == name {
+			spec.Variant, found = Variant(i), true
+		}
+	}
+	if !found {
+		return alsoSpec{}, errors.New("possible variants are 'passphrase', 'password', 'hex', 'base64'")
+	}
+	if hasBits {
+		n, err := strconv.ParseFloat(bitsStr, 64)
+		if err != nil {
+			return alsoSpec{}, err
+		} else if !(n > 0) || math.IsInf(n, 0) {
+			return alsoSpec{}, strconv.ErrRange
+		}
+		spec.Bits = n
+	}
+	return spec, nil
+}
+
+func parseInsertSpec(value string) (insertSpec, error) {
+	posStr, cset, ok := strings.Cut(value, ":")
+	if !ok {
+		return insertSpec{}, errors.New("must be in the form POS:CSET")
+	}
+	pos, err := strconv.ParseUint(posStr, 10, strconv.IntSize)
+	if err != nil {
+		return insertSpec{}, err
+	} else if pos == 0 {
+		return insertSpec{}, strconv.ErrRange
+	}
+	set, err := runeset.Parse(cset)
+	if err != nil {
+		return insertSpec{}, err
+	}
+	if set.Picker().Size() == 0 {
+		return insertSpec{}, errors.New("must contain at least 1 character")
+	}
+	return insertSpec{uint(pos), &set}, nil
+}
+
+func (c *Command) getAlsoGenerators() ([]labeledGenerator, error) {
+	var outputs []labeledGenerator
+	for _, spec := range c.Also {
+		alt := *c
+		alt.Variant, alt.Bits, alt.Length, alt.Also = spec.Variant, spec.Bits, 0, nil
+		if alt.Variant != c.Variant {
+			alt.WordsOnly, alt.Shuffle, alt.Acrostic = false, false, false
+			if alt.Variant != Hexadecimal && alt.Variant != Base64 {
+				alt.Pepper, alt.Split = nil, 0
+			}
+		}
+		if alt.Variant == Password && alt.Charset == nil {
+			set, err := runeset.Parse(`\g`)
+			if err != nil {
+				return nil, err
+			}
+			alt.Charset, alt.CharsetSpec = &set, `\g`
+		}
+		generator, bits, err := alt.getGenerator()
+		if err != nil {
+			return nil, fmt.Errorf("--also=%v: %w", variants[spec.Variant].Name, err)
+		}
+		if spec.Bits != 0 && bits < spec.Bits {
+			c.Warnf("--also=%v: generated strings have only %.2f bits of strength", variants[spec.Variant].Name, bits)
+		}
+		outputs = append(outputs, labeledGenerator{variants[spec.Variant].Name, generator, bits})
+	}
+	return outputs, nil
+}
+
+func (c *Command) getGenerator() (Generator, float64, error) {
+	generator, bits, err := c.getVariantGenerator()
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.HyphenateEvery != 0 {
+		generator = newHyphenateGenerator(generator, c.HyphenateEvery, c.HyphenateWith)
+	}
+	if c.Split != 0 {
+		// The shares are printed as one multi-line block, which the per-line
+		// output options would treat as a single string. --bits-summary is
+		// printed once to the standard error, so it is left alone.
+		if c.HyphenateEvery != 0 || c.Wrap != 0 || len(c.Also) != 0 || c.Hash != "" || c.EnsurePrintableASCII || c.Unique || c.Numbered || c.MaxBytes != 0 || (c.ShowBits && !c.BitsSummary) {
+			return nil, 0, options.Errorf("--split cannot be used with --hyphenate-every, --wrap, --also, --hash, --ensure-printable-ascii, --unique, --count-from, --max-bytes or --show-bits")
+		}
+		alphabet := hexAlphabet
+		if c.Variant == Base64 {
+			alphabet = base64Alphabet
+		}
+		generator = newSplitGenerator(generator, alphabet, c.Split, c.Count != 1)
+	}
+	return generator, bits, nil
+}
+
+func (c *Command) getShuffleGenerator(picker *runeset.Picker) (Generator, float64, error) {
+	if c.FirstChars != nil || c.LastChars != nil || len(c.Inserts) != 0 || c.MinClasses != 0 || c.MinUniqueChars != 0 || c.MinDigits != 0 || c.MinSymbols != 0 || c.RejectSequential || c.MaxBytes != 0 {
+		return nil, 0, options.Errorf("--shuffle cannot be used with --first-char-class, --last-char-class, --insert, --min-classes, --min-unique-chars, --min-digits, --min-symbols, --reject-sequential or --max-bytes")
+	}
+
+	size := uint(picker.Size())
+	shuffleBits := func(nchars uint) float64 {
+		var bits float64
+		for i := range nchars {
+			bits += math.Log2(float64(size - i))
+		}
+		return bits
+	}
+
+	nchars := size
+	length, target := c.lengthAndTarget(0)
+	if length > size {
+		return nil, 0, fmt.Errorf("%w: --shuffle: the charset contains only %d characters", ErrConstraints, size)
+	}
+	if length != 0 || target != 0 {
+		nchars = length
+		for nchars < size && shuffleBits(nchars) < target {
+			nchars++
+		}
+	}
+	c.Debugf("charset size: %d", size)
+	c.Debugf("characters per password: %d", nchars)
+	return newShuffleGenerator(picker.Runes(), nchars), shuffleBits(nchars), nil
+}
+
+func (c *Command) getWeightedGenerator(defaultBits uint) (Generator, float64, error) {
+	if c.CharsetSpec == "" {
+		return nil, 0, options.Errorf("--weighted requires -p or -P")
+	}
+	if c.FirstChars != nil || c.LastChars != nil || len(c.Inserts) != 0 || c.MinClasses != 0 || c.MinUniqueChars != 0 || c.MinDigits != 0 || c.MinSymbols != 0 || c.RejectSequential || c.MaxBytes != 0 || c.PrintableOnly || c.ExcludeHomoglyphs || c.Shuffle {
+		return nil, 0, options.Errorf("--weighted cannot be used with --first-char-class, --last-char-class, --insert, --min-classes, --min-unique-chars, --min-digits, --min-symbols, --reject-sequential, --max-bytes, --printable-only, --exclude-homoglyphs or --shuffle")
+	}
+	picker, err := runeset.ParseWeighted(c.CharsetSpec)
+	if err != nil {
+		return nil, 0, err
+	}
+	bitsPerElem := picker.Entropy()
+	nchars := c.getNumOfElems(bitsPerElem, defaultBits)
+	c.Debugf("charset size: %d", picker.Size())
+	c.Debugf("entropy per character: %.2f bits", bitsPerElem)
+	c.Debugf("characters per password: %d", nchars)
+	generator := func() string {
+		return picker.RandomStringFrom(random, int(nchars))
+	}
+	return generator, bitsPerElem * float64(nchars), nil
+}
+
+func (c *Command) getVariantGenerator() (Generator, float64, error) {
+	if c.Pepper != nil && c.Variant != Hexadecimal && c.Variant != Base64 {
+		return nil, 0, options.Errorf("--pepper can be used only with --hex or --base64")
+	}
+	if c.Split != 0 && c.Variant != Hexadecimal && c.Variant != Base64 {
+		return nil, 0, options.Errorf("--split can be used only with --hex or --base64")
+	}
+	if c.WordsOnly {
+		if c.Variant != Passphrase {
+			return nil, 0, options.Errorf("--words-only can be used only with passphrases")
+		}
+		// validate rejects -s with --words-only, so this only replaces a
+		// separator from the environment or the configuration file.
+		c.Separator = "\n"
+	}
+	if c.Shuffle && c.Variant != Password {
+		return nil, 0, options.Errorf("--shuffle can be used only with -p, -P or --emoji")
+	}
+
+	if int(c.Variant) >= len(variants) {
+		panic("genpass: invalid Variant")
+	}
+	return variants[c.Variant].Generator(c, c.defaultBits(c.Variant))
+}
+
+func (c *Command) getPasswordGenerator(defaultBits uint) (Generator, float64, error) {
+	if c.AssertNoDupes && c.CharsetSpec != "" {
+		dupes, err := runeset.ParseDuplicates(c.CharsetSpec)
+		if err != nil {
+			return nil, 0, err
+		}
+		if dupes.Len() != 0 {
+			text, _ := dupes.MarshalText()
+			return nil, 0, options.Errorf("--assert-no-dupes-in-charset: CSET lists %s more than once", text)
+		}
+	}
+	if c.Weighted {
+		return c.getWeightedGenerator(defaultBits)
+	}
+	charset, err := c.getCharset()
+	if err != nil {
+		return nil, 0, err
+	}
+	picker := charset.Picker()
+	if c.AllowedCharsReport {
+		if report := riskyCharsReport(picker); report != "" {
+			c.Warnf("the charset contains characters that may need quoting or escaping in %v", report)
+		}
+	}
+	if c.Shuffle {
+		return c.getShuffleGenerator(picker)
+	}
+	bitsPerElem := picker.Bits()
+
+	first, last, both := picker, picker, picker
+	firstSet, lastSet, bothSet := charset, charset, charset
+	if c.FirstChars != nil {
+		set := charset.Intersect(c.FirstChars)
+		if first = set.Picker(); first.Size() == 0 {
+			return nil, 0, errors.New("--first-char-class has no characters in common with the charset")
+		}
+		firstSet, bothSet = &set, &set
+		both = first
+	}
+	if c.LastChars != nil {
+		set := charset.Intersect(c.LastChars)
+		if last = set.Picker(); last.Size() == 0 {
+			return nil, 0, errors.New("--last-char-class has no characters in common with the charset")
+		}
+		lastSet, bothSet = &set, &set
+		if c.FirstChars != nil {
+			set := set.Intersect(c.FirstChars)
+			bothSet = &set
+		}
+		both = bothSet.Picker()
+	}
+	passwordBits := func(nchars uint) float64 {
+		var bits float64
+		switch nchars {
+		case 0:
+			return 0
+		case 1:
+			bits = both.Bits()
+		default:
+			bits = first.Bits() + last.Bits() + bitsPerElem*float64(nchars-2)
+		}
+		for _, spec := range c.Inserts {
+			if spec.Pos > nchars {
+				continue
+			}
+			replaced := picker
+			switch {
+			case nchars == 1:
+				replaced = both
+			case spec.Pos == 1:
+				replaced = first
+			case spec.Pos == nchars:
+				replaced = last
+			}
+			bits += spec.Chars.Picker().Bits() - replaced.Bits()
+		}
+		return bits
+	}
+
+	nchars := c.getNumOfElems(bitsPerElem, defaultBits)
+	_, target := c.lengthAndTarget(defaultBits)
+	for passwordBits(nchars) < target {
+		nchars++
+	}
+	if c.MaxBytes != 0 {
+		// Shorten the password before generating it, rather than trimming
+		// the result, so that the last character still comes from
+		// --last-char-class.
+		maxRuneLen := uint(utf8.RuneLen(picker.Get(picker.Size() - 1)))
+		for _, spec := range c.Inserts {
+			chars := spec.Chars.Picker()
+			maxRuneLen = max(maxRuneLen, uint(utf8.RuneLen(chars.Get(chars.Size()-1))))
+		}
+		fits, err := c.getNumOfFits(maxRuneLen, 0)
+		if err != nil {
+			return nil, 0, err
+		}
+		nchars = min(nchars, fits)
+		c.warnMaxBytes(passwordBits(nchars), target)
+	}
+	if nchars == 1 {
+		if both.Size() == 0 {
+			return nil, 0, errors.New("--first-char-class and --last-char-class have no characters in common with the charset")
+		}
+		first, last = both, both
+	}
+	generator := newPasswordGenerator(picker, nchars, first, last)
+	for _, spec := range c.Inserts {
+		generator = newInsertGenerator(generator, spec.Pos, spec.Chars.Picker())
+	}
+	c.Debugf("charset size: %d", picker.Size())
+	c.Debugf("characters per password: %d", nchars)
+	bits := passwordBits(nchars)
+	for _, spec := range c.Inserts {
+		if spec.Pos > nchars {
+			return nil, 0, fmt.Errorf("%w: --insert: position %d is beyond the password length %d", ErrConstraints, spec.Pos, nchars)
+		}
+	}
+
+	// The filters below see the characters of --first-char-class,
+	// --last-char-class and --insert too, so their acceptance is computed
+	// from the set each position is drawn from.
+	positions := make([]*runeset.RuneSet, nchars)
+	for i := range positions {
+		positions[i] = charset
+	}
+	switch {
+	case nchars == 1:
+		positions[0] = bothSet
+	case nchars > 1:
+		positions[0], positions[nchars-1] = firstSet, lastSet
+	}
+	for _, spec := range c.Inserts {
+		positions[spec.Pos-1] = spec.Chars
+	}
+
+	if c.MinClasses != 0 {
+		sizes := positionClassSizes(positions)
+		var nclasses int
+		for i := range numCharClasses {
+			if slices.ContainsFunc(sizes, func(size [numCharClasses]int64) bool { return size[i] != 0 }) {
+				nclasses++
+			}
+		}
+		if int(c.MinClasses) > nclasses {
+			return nil, 0, fmt.Errorf("%w: --min-classes: the charset contains only %d classes", ErrConstraints, nclasses)
+		}
+		p := minClassesProbability(sizes, int(c.MinClasses))
+		if p < minAcceptance {
+			return nil, 0, fmt.Errorf("%w: --min-classes: %d characters are too short to contain %d classes", ErrConstraints, nchars, c.MinClasses)
+		}
+		generator = newFilterGenerator(generator, func(s string) bool {
+			return countCharClasses(s) >= int(c.MinClasses)
+		}, c.retryLimit())
+		bits += math.Log2(p)
+	}
+	if c.MinUniqueChars != 0 {
+		if int64(c.MinUniqueChars) > picker.Size() {
+			return nil, 0, fmt.Errorf("%w: --min-unique-chars: the charset contains only %d characters", ErrConstraints, picker.Size())
+		}
+		if c.MinUniqueChars > nchars {
+			return nil, 0, fmt.Errorf("%w: --min-unique-chars: %d characters are too short to contain %d distinct characters", ErrConstraints, nchars, c.MinUniqueChars)
+		}
+		p := minUniqueProbability(picker.Size(), nchars, c.MinUniqueChars)
+		if p < minAcceptance {
+			return nil, 0, fmt.Errorf("%w: --min-unique-chars: %d distinct characters out of %d are too unlikely", ErrConstraints, c.MinUniqueChars, nchars)
+		}
+		generator = newFilterGenerator(generator, func(s string) bool {
+			return countUniqueChars(s) >= c.MinUniqueChars
+		}, c.retryLimit())
+		bits += math.Log2(p)
+	}
+	if c.MinDigits != 0 || c.MinSymbols != 0 {
+		digits, symbols := digitAndSymbolSizes(charset)
+		for _, spec := range c.Inserts {
+			d, s := digitAndSymbolSizes(spec.Chars)
+			digits, symbols = digits+d, symbols+s
+		}
+		if c.MinDigits != 0 && digits == 0 {
+			return nil, 0, fmt.Errorf("%w: --min-digits: the charset contains no digits", ErrConstraints)
+		}
+		if c.MinSymbols != 0 && symbols == 0 {
+			return nil, 0, fmt.Errorf("%w: --min-symbols: the charset contains no symbols", ErrConstraints)
+		}
+		if c.MinDigits+c.MinSymbols > nchars {
+			return nil, 0, fmt.Errorf("%w: --min-digits/--min-symbols: %d characters are too short to contain %d digits and %d symbols", ErrConstraints, nchars, c.MinDigits, c.MinSymbols)
+		}
+		p := minCountsProbability(positions, c.MinDigits, c.MinSymbols)
+		if p < minAcceptance {
+			return nil, 0, fmt.Errorf("%w: --min-digits/--min-symbols: %d digits and %d symbols out of %d characters are too unlikely", ErrConstraints, c.MinDigits, c.MinSymbols, nchars)
+		}
+		generator = newFilterGenerator(generator, func(s string) bool {
+			digits, symbols := countDigitsAndSymbols(s)
+			return digits >= c.MinDigits && symbols >= c.MinSymbols
+		}, c.retryLimit())
+		bits += math.Log2(p)
+	}
+	if c.RejectSequential {
+		p := noSequentialProbability(positions)
+		if p < minAcceptance {
+			return nil, 0, fmt.Errorf("%w: --reject-sequential: the charset is too small to avoid sequential characters", ErrConstraints)
+		}
+		generator = newFilterGenerator(generator, func(s string) bool {
+			return !hasSequentialRun(s)
+		}, c.retryLimit())
+		bits += math.Log2(p)
+	}
+	return generator, bits, nil
+}
+
+func (c *Command) getHexGenerator(defaultBits uint) (Generator, float64, error) {
+	bitsPerElem := float64(4)
+	nchars := c.getNumOfElems(bitsPerElem, defaultBits)
+	if c.MaxBytes != 0 {
+		nchars = min(nchars, c.MaxBytes)
+		_, target := c.lengthAndTarget(defaultBits)
+		c.warnMaxBytes(bitsPerElem*float64(nchars), target)
+	}
+	c.Debugf("characters per string: %d", nchars)
+	return newHexGenerator(nchars, c.Pepper), bitsPerElem * float64(nchars), nil
+}
+
+func (c *Command) getBase64Generator(defaultBits uint) (Generator, float64, error) {
+	bitsPerElem := float64(6)
+	nchars := c.getNumOfElems(bitsPerElem, defaultBits)
+	if c.MaxBytes != 0 {
+		nchars = min(nchars, c.MaxBytes)
+		_, target := c.lengthAndTarget(defaultBits)
+		c.warnMaxBytes(bitsPerElem*float64(nchars), target)
+	}
+	c.Debugf("characters per string: %d", nchars)
+	return newBase64Generator(nchars, c.Pepper), bitsPerElem * float64(nchars), nil
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) (err error) {
+	c := &Command{
+		Logger:         Logger{Writer: stderr},
+		Stdin:          stdin,
+		Stdout:         stdout,
+		Count:          1,
+		GuessRate:      1e10,
+		Variant:        Passphrase,
+		Wordlist:       "eff-large",
+		WordlistFormat: "plain",
+		Separator:      " ",
+		HyphenateWith:  "-",
+	}
+
+	if path, ok := findConfigOption(args); ok {
+		if err := c.loadConfig(path, true); err != nil {
+			return err
+		}
+	} else if path := defaultConfigPath(); path != "" {
+		if err := c.loadConfig(path, false); err != nil {
+			return err
+		}
+	}
+
+	if err := c.loadEnv(); err != nil {
+		return err
+	}
+
+	c.given = nil
+	switch _, err := options.Parse(c, args); {
 	case errors.Is(err, options.ErrHelp):
 		usage := strings.ReplaceAll(USAGE, "$NAME", NAME)
-		fmt.Print(usage)
+		usage = strings.ReplaceAll(usage, "$CONFIG", defaultConfigPath())
+		fmt.Fprint(c.Stdout, usage)
 		return nil
 	case errors.Is(err, options.ErrVersion):
 		version := VERSION
 		if bi, ok := debug.ReadBuildInfo(); ok {
 			version = bi.Main.Version
 		}
-		fmt.Printf("%v %v\n", NAME, version)
+		fmt.Fprintf(c.Stdout, "%v %v\n", NAME, version)
+		fmt.Fprintf(c.Stdout, "go version: %v\n", runtime.Version())
+		if c.Derive {
+			fmt.Fprintf(c.Stdout, "random source: HMAC-SHA256 keystream (--derive)\n")
+			fmt.Fprintf(c.Stdout, "note: the output is derived deterministically and is not cryptographically random\n")
+		} else if c.TestSeed != nil {
+			fmt.Fprintf(c.Stdout, "random source: ChaCha8 seeded with %d (--test-seed, not secret)\n", *c.TestSeed)
+		} else if c.EntropySource != "" {
+			fmt.Fprintf(c.Stdout, "random source: file:%v (--entropy-source, not crypto/rand)\n", c.EntropySource)
+		} else {
+			fmt.Fprintf(c.Stdout, "random source: %v\n", randomSourceName())
+		}
 		return nil
 	case err != nil:
 		return err
 	}
+	if err := c.validate(); err != nil {
+		return err
+	}
+	c.applyStrengthPreset()
+
+	if c.EntropySource != "" {
+		if c.Derive {
+			return options.Errorf("--entropy-source cannot be used with --derive")
+		}
+		f, err := os.Open(c.EntropySource)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		saved := random
+		defer func() { random = saved }()
+		random = entropyFile{f}
+		c.Warnf("reading random bytes from %v instead of crypto/rand; the output is only as unpredictable as this source", c.EntropySource)
+	}
+
+	if c.TestSeed != nil {
+		if !c.Insecure {
+			return options.Errorf("--test-seed requires --i-know-this-is-insecure")
+		}
+		if c.Derive || c.EntropySource != "" {
+			return options.Errorf("--test-seed cannot be used with --derive or --entropy-source")
+		}
+		saved := random
+		defer func() { random = saved }()
+		random = newSeededReader(*c.TestSeed)
+		fmt.Fprintf(c.Writer, "%v: WARNING: --test-seed=%d makes the output reproducible by anyone; NEVER use it as a secret\n", NAME, *c.TestSeed)
+	} else if c.Insecure {
+		return options.Errorf("--i-know-this-is-insecure can be used only with --test-seed")
+	}
+
+	source := random
+	if c.Derive {
+		if c.Site == "" || c.Master == "" {
+			return options.Errorf("--derive requires --site and --master")
+		}
+		random = newKeystreamReader(deriveKey(c.Master, c.Site))
+	} else if c.Site != "" || c.Master != "" {
+		return options.Errorf("--site and --master require --derive")
+	}
+
+	// Every mode below may draw random bytes, so failures of the random
+	// source are recovered from here on.
+	counter := &countingReader{r: random}
+	random = counter
+	var consumed bytes.Buffer
+	if c.ShowEntropyBytes {
+		c.Warnf("--show-entropy-bytes exposes the random bytes the output is made from; do not use the output as a secret")
+		random = io.TeeReader(counter, &consumed)
+	}
+	defer func() {
+		random = source
+		if r := recover(); r != nil {
+			if counter.err != nil {
+				err = fmt.Errorf("%w: %w", ErrRandomSource, counter.err)
+			} else if e, ok := r.(error); ok && errors.Is(e, ErrConstraints) {
+				err = e
+			} else {
+				panic(r)
+			}
+		}
+	}()
+
+	if c.Histogram != 0 {
+		return c.printHistogram()
+	}
+	if c.Sample != 0 {
+		return c.printSample(c.Stdout)
+	}
+	if c.MnemonicChecksum {
+		return checkMnemonic(c.Stdin, c.Stdout)
+	}
+	if c.WordlistCheck {
+		return c.checkWordlist()
+	}
+	if c.WordlistStatsJSON {
+		return c.printWordlistStats()
+	}
+	if c.DebugCharset {
+		return c.printCharset()
+	}
+	if c.Interactive {
+		return c.runInteractive()
+	}
+	if c.ListVariants {
+		c.printVariants()
+		return nil
+	}
 
 	generator, bits, err := c.getGenerator()
 	if err != nil {
 		return err
 	}
+	if c.Bits != 0 && bits < c.Bits {
+		c.Warnf("generated strings have only %.2f bits of strength", bits)
+	}
+	outputs := []labeledGenerator{{variants[c.Variant].Name, generator, bits}}
+	also, err := c.getAlsoGenerators()
+	if err != nil {
+		return err
+	}
+	outputs = append(outputs, also...)
+	if c.Unique {
+		if c.Count == 0 {
+			return options.Errorf("--unique cannot be used with --count=0")
+		}
+		for i, out := range outputs {
+			if space := math.Round(math.Exp2(out.Bits)); space < float64(c.Count) {
+				return fmt.Errorf("%w: --unique: %v has only about %.0f possible strings, fewer than --count=%d", ErrConstraints, out.Label, space, c.Count)
+			}
+			outputs[i].Generator = newUniqueGenerator(out.Generator, c.retryLimit())
+		}
+	}
+
+	if c.Benchmark != 0 {
+		c.printBenchmark(outputs, counter)
+		return nil
+	}
+	if c.BitsSummary {
+		for _, out := range outputs {
+			label := "strength"
+			if len(outputs) > 1 {
+				label = "strength of " + out.Label
+			}
+			fmt.Fprintf(c.Writer, "%v: %v: %v%v%v\n", NAME, label, bitsColor(out.Bits), c.strength(out.Bits), colorterm.Reset)
+		}
+	}
+	start := time.Now()
+
+	if c.Count == 0 {
+		signal.Ignore(syscall.SIGPIPE)
+	}
+
+	prog := c.newProgress()
+	defer func() { prog.done() }()
 
-	for range c.Count {
-		fmt.Print(generator())
-		if c.ShowBits {
-			fmt.Printf("\t\t%v(%.2f bits)%v", Gray, bits, colorterm.Reset)
+	var count uint
+loop:
+	for ; c.Count == 0 || count < c.Count; count++ {
+		for i, out := range outputs {
+			line := out.Generator()
+			if c.ShowEntropyBytes {
+				fmt.Fprintf(c.Writer, "%v: random bytes: %x\n", NAME, consumed.Bytes())
+				consumed.Reset()
+			}
+			if c.EnsurePrintableASCII {
+				if err := checkPrintableASCII(line, c.WordsOnly); err != nil {
+					return err
+				}
+			}
+			var hash string
+			if c.Hash != "" {
+				if hash, err = hashPassword(c.Hash, line); err != nil {
+					return err
+				}
+			}
+			var initials string
+			if c.Acrostic && i == 0 {
+				initials = c.acrostic(line)
+			}
+			if c.Wrap != 0 {
+				line = wrapLine(line, c.Wrap, c.EscapeOutput)
+			} else if c.EscapeOutput {
+				line = escapeNonASCII(line)
+			}
+			if c.Preview {
+				line = colorizeClasses(line)
+			}
+			if len(outputs) > 1 {
+				line = out.Label + "\t" + line
+			}
+			if c.Numbered {
+				line = fmt.Sprintf("%d\t%v", c.CountFrom+count, line)
+			}
+			if c.ResultDelimiter != nil && count != 0 && i == 0 {
+				line = *c.ResultDelimiter + "\n" + line
+			} else if c.WordsOnly && count != 0 && i == 0 {
+				line = "\n" + line
+			}
+			if c.Hash != "" {
+				line += "\t" + hash
+			}
+			if c.Acrostic && i == 0 {
+				line += "\t\t" + initials
+			}
+			if c.ShowBits && !c.BitsSummary {
+				line += fmt.Sprintf("\t\t%v(%v)%v", bitsColor(out.Bits), c.strength(out.Bits), colorterm.Reset)
+			}
+			if _, err := fmt.Fprintln(c.Stdout, line); errors.Is(err, syscall.EPIPE) {
+				break loop
+			} else if err != nil {
+				return err
+			}
 		}
-		fmt.Println()
+		prog.update(count + 1)
 	}
+	prog.done()
+	prog = nil
+
+	for _, out := range outputs {
+		c.Debugf("strength of %v: %.2f bits", out.Label, out.Bits)
+	}
+	c.Debugf("generated %d strings in %v", count, time.Since(start))
+	c.Debugf("random bytes consumed: %d", counter.n)
 
 	return nil
 }
 
+func (c *Command) printBenchmark(outputs []labeledGenerator, counter *countingReader) {
+	var bits float64
+	for _, out := range outputs {
+		bits += out.Bits
+	}
+	start := time.Now()
+	for range c.Benchmark {
+		for _, out := range outputs {
+			out.Generator()
+		}
+	}
+	elapsed := time.Since(start)
+	seconds := elapsed.Seconds()
+	fmt.Fprintf(c.Writer, "%v: benchmark: generated %d strings in %v\n", NAME, c.Benchmark, elapsed)
+	fmt.Fprintf(c.Writer, "%v: benchmark: %.0f strings/s, %.0f random bytes/s, %.0f bits of strength/s\n", NAME,
+		float64(c.Benchmark)/seconds, float64(counter.n)/seconds, bits*float64(c.Benchmark)/seconds)
+}
+
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitCmdline     = 2
+	exitRandom      = 3
+	exitConstraints = 4
+	exitIO          = 5
+)
+
+func exitCode(err error) int {
+	var pathErr *fs.PathError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, options.ErrCmdline):
+		return exitCmdline
+	case errors.Is(err, ErrRandomSource):
+		return exitRandom
+	case errors.Is(err, ErrConstraints):
+		return exitConstraints
+	case errors.As(err, &pathErr):
+		return exitIO
+	default:
+		return exitError
+	}
+}
+
 func main() {
-	if err := run(os.Args[1:]); err != nil {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
 		fmt.Fprintf(os.Stderr, "%v: error: %v\n", NAME, err)
-		if errors.Is(err, options.ErrCmdline) {
-			os.Exit(2)
-		}
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
@@ -0,0 +1,1529 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/cions/genpass/internal/runeset"
+	"github.com/cions/genpass/internal/wordlists"
+	"github.com/cions/go-colorterm"
+	"github.com/cions/go-options"
+	"golang.org/x/text/language"
+)
+
+func writeTempFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "wordlist.txt")
+	if err := os.WriteFile(path, []byte(content), 0o666); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGetWordlist_column(t *testing.T) {
+	path := writeTempFile(t, "11111\tabacus\n11112\tabdomen\n11113 abide\n")
+
+	c := &Command{WordlistColumn: 2}
+	wordlist, _, err := c.getWordlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"abacus", "abdomen", "abide"}; !slices.Equal(wordlist, want) {
+		t.Errorf("expected %v, but got %v", want, wordlist)
+	}
+
+	c = &Command{WordlistColumn: 3}
+	if _, _, err := c.getWordlist(path); err == nil {
+		t.Errorf("expected a non-nil error")
+	}
+}
+
+func TestGetWordlist_emptyLines(t *testing.T) {
+	path := writeTempFile(t, "\nfoo\n\n  \nbar\t\n\t\nbaz\n\n")
+
+	c := &Command{}
+	wordlist, _, err := c.getWordlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"foo", "bar", "baz"}; !slices.Equal(wordlist, want) {
+		t.Errorf("expected %q, but got %q", want, wordlist)
+	}
+
+	c = &Command{AllowEmptyLines: true, TrimWhitespace: "none"}
+	wordlist, _, err = c.getWordlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"", "foo", "", "  ", "bar\t", "\t", "baz", ""}; !slices.Equal(wordlist, want) {
+		t.Errorf("expected %q, but got %q", want, wordlist)
+	}
+
+	c = &Command{WordlistFormat: "tsv"}
+	wordlist, weights, err := c.getWordlist(writeTempFile(t, "foo\t1\n\nbar\t2\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"foo", "bar"}; !slices.Equal(wordlist, want) || !slices.Equal(weights, []uint64{1, 2}) {
+		t.Errorf("expected %q, but got %q %v", want, wordlist, weights)
+	}
+}
+
+func TestGetWordlist_trim(t *testing.T) {
+	path := writeTempFile(t, "foo\r\n bar \r\n\tbaz\r\r\n")
+
+	tests := []struct {
+		mode string
+		want []string
+	}{
+		{"", []string{"foo", "bar", "baz"}},
+		{"both", []string{"foo", "bar", "baz"}},
+		{"trailing", []string{"foo", " bar", "\tbaz"}},
+		{"none", []string{"foo", " bar ", "\tbaz\r"}},
+	}
+	for _, tt := range tests {
+		c := &Command{}
+		if tt.mode != "" {
+			if err := c.Option("--trim-wordlist-whitespace", tt.mode, true); err != nil {
+				t.Fatalf("%q: unexpected error: %v", tt.mode, err)
+			}
+		}
+		wordlist, _, err := c.getWordlist(path)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.mode, err)
+		}
+		if !slices.Equal(wordlist, tt.want) {
+			t.Errorf("%q: expected %q, but got %q", tt.mode, tt.want, wordlist)
+		}
+	}
+
+	c := &Command{Length: 20}
+	if err := c.Option("--wordlist", path, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	generator, _, err := c.getGenerator()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := generator(); strings.ContainsAny(got, "\r\t") {
+		t.Errorf("expected no CR or tab in the passphrase, but got %q", got)
+	}
+}
+
+func TestGetWordlist_sep(t *testing.T) {
+	path := writeTempFile(t, "1,abacus,x\n2,abdomen,y\n3,abide,z\n")
+
+	c := &Command{WordlistColumn: 2, WordlistSep: ","}
+	wordlist, _, err := c.getWordlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"abacus", "abdomen", "abide"}; !slices.Equal(wordlist, want) {
+		t.Errorf("expected %v, but got %v", want, wordlist)
+	}
+
+	c = &Command{}
+	if err := c.Option("--wordlist-sep", ",", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wordlist, _, err = c.getWordlist(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"1", "2", "3"}; !slices.Equal(wordlist, want) {
+		t.Errorf("--wordlist-sep=,: expected %v, but got %v", want, wordlist)
+	}
+}
+
+func TestPattern_stdinOnce(t *testing.T) {
+	c := &Command{Stdin: strings.NewReader("foo\nbar\n"), Pattern: []string{"-", "nouns", "-"}, WordlistFormat: "plain", Separator: " ", Length: 3}
+	generator, _, err := c.getGenerator()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	words := strings.Split(generator(), " ")
+	for _, i := range []int{0, 2} {
+		if words[i] != "foo" && words[i] != "bar" {
+			t.Errorf("unexpected word %q from the standard input", words[i])
+		}
+	}
+}
+
+func TestRun_stdin(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+	t.Cleanup(func() { random = saved })
+
+	tests := []struct {
+		args  []string
+		stdin string
+		want  string
+	}{
+		{[]string{"-w", "-", "-l", "2"}, "foo\nbar\n", "foo foo\n"},
+		{[]string{"--mnemonic-checksum"}, "legal winner thank year wave sausage worth useful legal winner thank yellow\n", "valid: 128-bit entropy 7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f\n"},
+	}
+	for _, tt := range tests {
+		random = bytes.NewReader(make([]byte, 1024))
+		var stdout bytes.Buffer
+		if err := run(tt.args, strings.NewReader(tt.stdin), &stdout, io.Discard); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", tt.args, err)
+			continue
+		}
+		if got := stdout.String(); got != tt.want {
+			t.Errorf("run(%q): expected %q, but got %q", tt.args, tt.want, got)
+		}
+	}
+
+	args := []string{"--mnemonic-checksum"}
+	if err := run(args, strings.NewReader("abandon abandon abandon\n"), io.Discard, io.Discard); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Errorf("run(%q): expected %v, but got %v", args, ErrInvalidMnemonic, err)
+	}
+}
+
+func TestGetCharset_printableOnly(t *testing.T) {
+	c := &Command{PrintableOnly: true}
+	if err := c.Option("--password-with", `\x00-\x7F`, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	charset, err := c.getCharset()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := charset.String(), "!-~"; got != want {
+		t.Errorf("expected %v, but got %v", want, got)
+	}
+}
+
+func TestGetCharset_excludeHomoglyphs(t *testing.T) {
+	tests := []struct {
+		cset string
+		want string
+	}{
+		{`a-cасб`, "a-cб-б"},
+		{`АΑB`, "B-BА-А"},
+		{`xyz`, "x-z"},
+	}
+
+	for _, tt := range tests {
+		c := &Command{ExcludeHomoglyphs: true}
+		if err := c.Option("--password-with", tt.cset, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		charset, err := c.getCharset()
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.cset, err)
+		}
+		if got := charset.String(); got != tt.want {
+			t.Errorf("%q: expected %q, but got %q", tt.cset, tt.want, got)
+		}
+	}
+
+	c := &Command{ExcludeHomoglyphs: true}
+	if err := c.Option("--password-with", `aа`, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.getCharset(); err == nil {
+		t.Errorf("expected a non-nil error")
+	}
+}
+
+func TestReadAlphabet(t *testing.T) {
+	set, err := readAlphabet(writeTempFile(t, "€$£\n¥₩\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := string(set.Picker().Runes()), "$£¥₩€"; got != want {
+		t.Errorf("expected %v, but got %v", want, got)
+	}
+
+	for _, content := range []string{"", "a\n", "abca", "a\xFFb"} {
+		if _, err := readAlphabet(writeTempFile(t, content)); err == nil {
+			t.Errorf("readAlphabet(%q): expected a non-nil error", content)
+		}
+	}
+}
+
+func TestPrefixPairs(t *testing.T) {
+	words := []string{"youth", "you", "apple", "young", "yo", "banana", "app"}
+	want := [][2]string{
+		{"app", "apple"},
+		{"yo", "you"},
+		{"yo", "young"},
+		{"yo", "youth"},
+		{"you", "young"},
+		{"you", "youth"},
+	}
+	if got := prefixPairs(words); !slices.Equal(got, want) {
+		t.Errorf("expected %v, but got %v", want, got)
+	}
+
+	if got := prefixPairs([]string{"abc", "abd", "bcd"}); len(got) != 0 {
+		t.Errorf("expected no pairs, but got %v", got)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("entropy source unavailable")
+}
+
+func TestRun_output(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved, enabled := random, colorterm.Enabled
+	t.Cleanup(func() { random, colorterm.Enabled = saved, enabled })
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-x", "-b", "64"}, "0000000000000000\n"},
+		{[]string{"-x", "-l", "4", "-c", "2"}, "0000\n0000\n"},
+		{[]string{"-x", "-l", "4", "-c", "2", "--count-from", "9"}, "9\t0000\n10\t0000\n"},
+		{[]string{"-x", "-b", "64", "--wrap", "6"}, "000000\n000000\n0000\n"},
+		{[]string{"-P", "ab", "-l", "3", "--hyphenate-every", "1"}, "a-a-a\n"},
+		{[]string{"-u", "-l", "4", "--no-color", "--show-bits"}, "AAAA\t\t(24.00 bits)\n"},
+		{[]string{"-u", "-l", "4", "--no-color", "--estimate", "--guess-rate", "1e6"}, "AAAA\t\t(24.00 bits, ~8 seconds)\n"},
+		{[]string{"-u", "-l", "4", "--no-color", "-e", "--bits-base", "e"}, "AAAA\t\t(16.64 nats)\n"},
+		{[]string{"-u", "-l", "4", "--no-color", "-e", "--bits-base", "10"}, "AAAA\t\t(7.22 decimal digits)\n"},
+		{[]string{"-w", "eff-short1", "-l", "3", "--passphrase-acrostic"}, "acid acid acid\t\taaa\n"},
+		{[]string{"-w", "eff-short1", "-l", "2", "--passphrase-digits", "1", "--passphrase-acrostic"}, "0 acid acid\t\taa\n"},
+		{[]string{"-x", "-l", "4", "--also", "base64:24", "-c", "2"}, "hex\t0000\nbase64\tAAAA\nhex\t0000\nbase64\tAAAA\n"},
+		{[]string{"-x", "-l", "4", "-c", "3", "--delimiter-between-results", "---"}, "0000\n---\n0000\n---\n0000\n"},
+		{[]string{"-x", "-l", "4", "-c", "2", "--delimiter-between-results="}, "0000\n\n0000\n"},
+		{[]string{"-x", "-l", "4", "--also", "base64:24", "-c", "2", "--delimiter-between-results", "--"}, "hex\t0000\nbase64\tAAAA\n--\nhex\t0000\nbase64\tAAAA\n"},
+		{[]string{"-x", "-l", "4", "--also", "password:1", "--also", "hex:8"}, "hex\t0000\npassword\t!\nhex\t00\n"},
+		{[]string{"-P", "\u3042-\u3093", "-l", "3", "--output-encoding", "ascii-escaped"}, "\\u3042\\u3042\\u3042\n"},
+		{[]string{"-P", "\u3042-\u3093", "-l", "3", "--output-encoding", "ascii-escaped", "--wrap", "12"}, "\\u3042\\u3042\n\\u3042\n"},
+		{[]string{"-P", "a-z", "--last-char-class", "z", "--max-bytes", "3", "-q", "--no-color", "-e"}, "aaz\t\t(9.40 bits)\n"},
+		{[]string{"--passphrase-from-sentence", "-l", "1", "--no-color", "-e"}, "The able acorn will accept boldly.\t\t(34.55 bits)\n"},
+		{[]string{"--passphrase-from-sentence", "-l", "1", "--case", "upper"}, "THE ABLE ACORN WILL ACCEPT BOLDLY.\n"},
+		{[]string{"--passphrase-template", "{adj}-{noun} {number}{digit}", "-q", "--no-color", "-e"}, "able-acorn 00\t\t(26.29 bits)\n"},
+		{[]string{"-w", "eff-short1", "-l", "2", "--append-from", `\d`, "--append-count", "2", "--no-color", "-e"}, "acid acid00\t\t(27.32 bits)\n"},
+		{[]string{"--pattern", "adj,noun", "-l", "3"}, "able acorn able\n"},
+		{[]string{"--pattern", "adj,noun,verb", "-e", "--no-color"}, "able acorn accept able acorn accept able acorn accept able\t\t(81.96 bits)\n"},
+		{[]string{"-P", "xyz", "--sample", "2"}, "x\nx\n"},
+		{[]string{"-P", "xyz", "--histogram", "4"}, "CHAR\tCOUNT\tRATIO\n'x'\t4\t3.0000\n'y'\t0\t0.0000\n'z'\t0\t0.0000\nchi-squared: 8.00 (2 degrees of freedom)\n"},
+	}
+	for _, tt := range tests {
+		random = bytes.NewReader(make([]byte, 1024))
+		var stdout, stderr bytes.Buffer
+		if err := run(tt.args, nil, &stdout, &stderr); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", tt.args, err)
+			continue
+		}
+		if got := stdout.String(); got != tt.want {
+			t.Errorf("run(%q): expected %q, but got %q", tt.args, tt.want, got)
+		}
+		if stderr.Len() != 0 {
+			t.Errorf("run(%q): unexpected stderr output %q", tt.args, stderr.String())
+		}
+	}
+}
+
+func TestRun_env(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+	t.Cleanup(func() { random = saved })
+
+	tests := []struct {
+		env  string
+		val  string
+		args []string
+		want string
+		code int
+	}{
+		{"GENPASS_WORDLIST", "", []string{"-l", "2"}, "abacus abacus\n", exitOK},
+		{"GENPASS_WORDLIST", "eff-short1", []string{"-l", "2"}, "acid acid\n", exitOK},
+		{"GENPASS_WORDLIST", "eff-short1", []string{"-w", "bip39", "-l", "2"}, "abandon abandon\n", exitOK},
+		{"GENPASS_WORDLIST", "eff-shrot1", []string{"-l", "2"}, "", exitCmdline},
+		{"GENPASS_BITS", "", []string{"-x"}, "00000000000000000000000000000000\n", exitOK},
+		{"GENPASS_BITS", "64", []string{"-x"}, "0000000000000000\n", exitOK},
+		{"GENPASS_BITS", "64", []string{"-x", "-b", "32"}, "00000000\n", exitOK},
+		{"GENPASS_BITS", "abc", []string{"-x"}, "", exitCmdline},
+		{"GENPASS_BITS", "abc", []string{"-x", "-b", "32"}, "", exitCmdline},
+		{"GENPASS_SEPARATOR", "", []string{"-w", "eff-short1", "-l", "2"}, "acid acid\n", exitOK},
+		{"GENPASS_SEPARATOR", "-", []string{"-w", "eff-short1", "-l", "2"}, "acid-acid\n", exitOK},
+		{"GENPASS_SEPARATOR", "-", []string{"-w", "eff-short1", "-l", "2", "-s", "."}, "acid.acid\n", exitOK},
+	}
+	for _, tt := range tests {
+		for _, opt := range envOptions {
+			t.Setenv(opt.env, "")
+		}
+		t.Setenv(tt.env, tt.val)
+		random = bytes.NewReader(make([]byte, 1024))
+		var stdout bytes.Buffer
+		err := run(tt.args, nil, &stdout, io.Discard)
+		if code := exitCode(err); code != tt.code {
+			t.Errorf("%s=%q run(%q): expected exit code %v, but got %v (%v)", tt.env, tt.val, tt.args, tt.code, code, err)
+		}
+		if got := stdout.String(); got != tt.want {
+			t.Errorf("%s=%q run(%q): expected %q, but got %q", tt.env, tt.val, tt.args, tt.want, got)
+		}
+	}
+}
+
+func TestRun_envWordlistKeepsVariant(t *testing.T) {
+	saved := random
+	t.Cleanup(func() { random = saved })
+	for _, opt := range envOptions {
+		t.Setenv(opt.env, "")
+	}
+	t.Setenv("GENPASS_WORDLIST", "eff-short1")
+	config := writeTempFile(t, "variant = hex\n")
+	wordlist := writeTempFile(t, "wordlist = eff-short1\nvariant = hex\n")
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--config", config, "-l", "4"}, "0000\n"},
+		{[]string{"--config", wordlist, "-l", "4"}, "0000\n"},
+		{[]string{"--config", config, "-w", "bip39", "-l", "2"}, "abandon abandon\n"},
+	}
+	for _, tt := range tests {
+		random = bytes.NewReader(make([]byte, 1024))
+		var stdout bytes.Buffer
+		if err := run(tt.args, nil, &stdout, io.Discard); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", tt.args, err)
+			continue
+		}
+		if got := stdout.String(); got != tt.want {
+			t.Errorf("run(%q): expected %q, but got %q", tt.args, tt.want, got)
+		}
+	}
+}
+
+func TestRun_maxBytesPassphrase(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+	t.Cleanup(func() { random = saved })
+	path := writeTempFile(t, "i\nj\n")
+	long := writeTempFile(t, "i\nj\nlonger\n")
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-w", long, "-l", "3", "--max-bytes", "5", "--no-color", "-e"}, "i i i\t\t(3.00 bits)\n"},
+		{[]string{"-w", long, "-l", "3", "--max-bytes", "6", "--no-color", "-e"}, "i\t\t(1.58 bits)\n"},
+		{[]string{"-w", path, "-l", "3", "--max-bytes", "5", "--no-color", "-e"}, "i i i\t\t(3.00 bits)\n"},
+		{[]string{"-w", path, "-l", "3", "--max-bytes", "5", "--case", "upper", "--locale", "tr", "--no-color", "-e"}, "\u0130 \u0130\t\t(2.00 bits)\n"},
+		{[]string{"-w", path, "-l", "3", "--max-bytes", "5", "--case", "mixed", "--locale", "tr", "--no-color", "-e"}, "i i\t\t(4.00 bits)\n"},
+		{[]string{"-w", path, "-l", "2", "--passphrase-digits", "2", "--max-bytes", "3", "--no-color", "-e"}, "i i\t\t(2.00 bits)\n"},
+		{[]string{"-w", path, "-l", "2", "--passphrase-digits", "2", "--max-bytes", "5", "--no-color", "-e"}, "0 i i\t\t(6.91 bits)\n"},
+	}
+	for _, tt := range tests {
+		random = bytes.NewReader(make([]byte, 1024))
+		var stdout bytes.Buffer
+		if err := run(tt.args, nil, &stdout, io.Discard); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", tt.args, err)
+			continue
+		}
+		if got := stdout.String(); got != tt.want {
+			t.Errorf("run(%q): expected %q, but got %q", tt.args, tt.want, got)
+		}
+	}
+}
+
+func TestRun_maxBytesWarning(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	tests := []struct {
+		args  []string
+		warns int
+	}{
+		{[]string{"-x", "--max-bytes", "8"}, 1},
+		{[]string{"-p", "--max-bytes", "8"}, 1},
+		{[]string{"--max-bytes", "8"}, 1},
+		{[]string{"-w", "bip39", "--max-bytes", "20"}, 1},
+		{[]string{"-x", "-b", "64", "--max-bytes", "8"}, 1},
+		{[]string{"-x", "-l", "4", "--max-bytes", "8"}, 0},
+		{[]string{"-x", "--max-bytes", "64"}, 0},
+	}
+	for _, tt := range tests {
+		var stderr bytes.Buffer
+		if err := run(tt.args, nil, io.Discard, &stderr); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", tt.args, err)
+			continue
+		}
+		if got := strings.Count(stderr.String(), "bits of strength"); got != tt.warns {
+			t.Errorf("run(%q): expected %v warnings, but got %q", tt.args, tt.warns, stderr.String())
+		}
+	}
+}
+
+func TestLengthAndTarget(t *testing.T) {
+	tests := []struct {
+		length     uint
+		bits       float64
+		lengthBits string
+		wantLength uint
+		wantTarget float64
+	}{
+		{0, 0, "", 0, 80},
+		{0, 0, "length", 0, 80},
+		{0, 0, "bits", 0, 80},
+		{10, 0, "", 10, 0},
+		{10, 0, "length", 10, 0},
+		{10, 0, "bits", 10, 0},
+		{0, 64, "", 0, 64},
+		{0, 64, "length", 0, 64},
+		{0, 64, "bits", 0, 64},
+		{10, 64, "", 10, 64},
+		{10, 64, "max", 10, 64},
+		{10, 64, "length", 10, 0},
+		{10, 64, "bits", 0, 64},
+	}
+
+	for _, tt := range tests {
+		c := &Command{Length: tt.length, Bits: tt.bits, LengthBits: tt.lengthBits}
+		length, target := c.lengthAndTarget(80)
+		if length != tt.wantLength || target != tt.wantTarget {
+			t.Errorf("lengthAndTarget(length=%v, bits=%v, %q): expected (%v, %v), but got (%v, %v)", tt.length, tt.bits, tt.lengthBits, tt.wantLength, tt.wantTarget, length, target)
+		}
+	}
+}
+
+func TestRun_lengthBits(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+	t.Cleanup(func() { random = saved })
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"-x", "-l", "4", "-b", "64"}, 16},
+		{[]string{"-x", "-l", "20", "-b", "64"}, 20},
+		{[]string{"-x", "-l", "4", "-b", "64", "--length-bits", "max"}, 16},
+		{[]string{"-x", "-l", "4", "-b", "64", "--length-bits", "length"}, 4},
+		{[]string{"-x", "-l", "20", "-b", "64", "--length-bits", "bits"}, 16},
+		{[]string{"-P", "ab", "-l", "4", "-b", "8"}, 8},
+		{[]string{"-P", "ab", "-l", "12", "-b", "8"}, 12},
+		{[]string{"-w", "eff-short1", "-l", "2", "-b", "40", "-s", "-"}, 4},
+		{[]string{"-w", "eff-short1", "-l", "5", "-b", "40", "-s", "-"}, 5},
+		{[]string{"-P", "abcdef", "--shuffle", "-l", "2", "-b", "8"}, 4},
+		{[]string{"-P", "abcdef", "--shuffle", "-l", "2", "-b", "8", "--length-bits", "length"}, 2},
+	}
+	for _, tt := range tests {
+		random = bytes.NewReader(make([]byte, 1024))
+		var stdout bytes.Buffer
+		if err := run(tt.args, nil, &stdout, io.Discard); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", tt.args, err)
+			continue
+		}
+		line := strings.TrimSuffix(stdout.String(), "\n")
+		got := utf8.RuneCountInString(line)
+		if slices.Contains(tt.args, "-w") {
+			got = len(strings.Split(line, "-"))
+		}
+		if got != tt.want {
+			t.Errorf("run(%q): expected %v elements, but got %v (%q)", tt.args, tt.want, got, line)
+		}
+	}
+}
+
+func TestRun_lengthBitsSources(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	bits := writeTempFile(t, "bits = 120\n")
+	length := writeTempFile(t, "length = 40\n")
+	both := writeTempFile(t, "bits = 120\nlength = 8\n")
+
+	tests := []struct {
+		env  string
+		args []string
+		want int
+	}{
+		{"100", []string{"-l", "3"}, 3},
+		{"100", []string{"-l", "3", "-b", "100"}, 8},
+		{"", []string{"--config", bits, "-x", "-l", "8"}, 8},
+		{"", []string{"--config", length, "-x", "-b", "64"}, 16},
+		{"", []string{"--config", both, "-x"}, 30},
+		{"", []string{"--config", both, "-x", "--length-bits", "length"}, 8},
+	}
+	for _, tt := range tests {
+		t.Setenv("GENPASS_BITS", tt.env)
+		var stdout bytes.Buffer
+		if err := run(tt.args, nil, &stdout, io.Discard); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", tt.args, err)
+			continue
+		}
+		line := strings.TrimSuffix(stdout.String(), "\n")
+		got := len(strings.Fields(line))
+		if slices.Contains(tt.args, "-x") {
+			got = len(line)
+		}
+		if got != tt.want {
+			t.Errorf("GENPASS_BITS=%q run(%q): expected %v elements, but got %v (%q)", tt.env, tt.args, tt.want, got, line)
+		}
+	}
+}
+
+func TestRun_entropySource(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+	path := filepath.Join(t.TempDir(), "entropy")
+	if err := os.WriteFile(path, make([]byte, 4), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-x", "-l", "4", "--entropy-source", "file:" + path}
+	if err := run(args, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run(%q): unexpected error: %v", args, err)
+	}
+	if got, want := stdout.String(), "0000\n"; got != want {
+		t.Errorf("run(%q): expected %q, but got %q", args, want, got)
+	}
+	if !strings.Contains(stderr.String(), "instead of crypto/rand") {
+		t.Errorf("run(%q): expected a warning, but got %q", args, stderr.String())
+	}
+	if random != saved {
+		t.Errorf("run(%q): the random source was not restored", args)
+	}
+
+	args = []string{"-x", "-l", "4", "-c", "3", "--entropy-source", "file:" + path}
+	if err := run(args, nil, io.Discard, io.Discard); !errors.Is(err, ErrRandomSource) {
+		t.Errorf("run(%q): expected %v, but got %v", args, ErrRandomSource, err)
+	}
+
+	args = []string{"-p", "--histogram", "5", "--entropy-source", "file:" + os.DevNull}
+	if err := run(args, nil, io.Discard, io.Discard); !errors.Is(err, ErrRandomSource) {
+		t.Errorf("run(%q): expected %v, but got %v", args, ErrRandomSource, err)
+	}
+}
+
+func TestRun_testSeed(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+
+	generate := func(seed string) string {
+		var stdout, stderr bytes.Buffer
+		args := []string{"-q", "-c", "3", "--test-seed", seed, "--i-know-this-is-insecure"}
+		if err := run(args, nil, &stdout, &stderr); err != nil {
+			t.Fatalf("run(%q): unexpected error: %v", args, err)
+		}
+		if !strings.Contains(stderr.String(), "NEVER use it as a secret") {
+			t.Errorf("run(%q): expected a warning even with -q, but got %q", args, stderr.String())
+		}
+		return stdout.String()
+	}
+	if a, b := generate("42"), generate("42"); a != b {
+		t.Errorf("--test-seed=42: expected the same output, but got %q and %q", a, b)
+	}
+	if a, b := generate("42"), generate("43"); a == b {
+		t.Errorf("--test-seed=42 and 43: expected different outputs, but got %q", a)
+	}
+	if random != saved {
+		t.Errorf("--test-seed: the random source was not restored")
+	}
+
+	for _, args := range [][]string{
+		{"--test-seed", "42"},
+		{"--i-know-this-is-insecure"},
+		{"--test-seed", "42", "--i-know-this-is-insecure", "--derive"},
+	} {
+		if err := run(args, nil, io.Discard, io.Discard); err == nil {
+			t.Errorf("run(%q): expected an error", args)
+		}
+	}
+}
+
+func TestRun_benchmark(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-x", "--benchmark", "100"}
+	if err := run(args, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run(%q): unexpected error: %v", args, err)
+	}
+	if got := stdout.String(); got != "" {
+		t.Errorf("run(%q): expected no output, but got %q", args, got)
+	}
+	if !strings.Contains(stderr.String(), "generated 100 strings") || !strings.Contains(stderr.String(), "random bytes/s") {
+		t.Errorf("run(%q): expected a benchmark report, but got %q", args, stderr.String())
+	}
+}
+
+func TestRun_unique(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var stdout bytes.Buffer
+	args := []string{"-P", `\d`, "-l", "2", "-c", "100", "--unique"}
+	if err := run(args, nil, &stdout, io.Discard); err != nil {
+		t.Fatalf("run(%q): unexpected error: %v", args, err)
+	}
+	lines := strings.Fields(stdout.String())
+	if len(lines) != 100 {
+		t.Errorf("run(%q): expected 100 lines, but got %d", args, len(lines))
+	}
+	slices.Sort(lines)
+	if n := len(slices.Compact(lines)); n != 100 {
+		t.Errorf("run(%q): expected 100 distinct lines, but got %d", args, n)
+	}
+
+	args = []string{"-P", `\d`, "-l", "2", "-c", "101", "--unique"}
+	if err := run(args, nil, io.Discard, io.Discard); !errors.Is(err, ErrConstraints) {
+		t.Errorf("run(%q): expected %v, but got %v", args, ErrConstraints, err)
+	}
+
+	args = []string{"-x", "-c", "0", "--unique"}
+	if err := run(args, nil, io.Discard, io.Discard); err == nil {
+		t.Errorf("run(%q): expected an error", args)
+	}
+}
+
+func TestRun_bitsSummary(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved, enabled := random, colorterm.Enabled
+	t.Cleanup(func() { random, colorterm.Enabled = saved, enabled })
+
+	tests := []struct {
+		args       []string
+		wantStdout string
+		wantStderr string
+	}{
+		{[]string{"-x", "-l", "4", "-c", "2", "--bits-summary"}, "0000\n0000\n", "genpass: strength: 16.00 bits\n"},
+		{[]string{"-x", "-l", "4", "--bits-summary", "--estimate", "--guess-rate", "1e3"}, "0000\n", "genpass: strength: 16.00 bits, ~33 seconds\n"},
+		{[]string{"-x", "-l", "4", "--also", "base64:24", "--bits-summary"}, "hex\t0000\nbase64\tAAAA\n", "genpass: strength of hex: 16.00 bits\ngenpass: strength of base64: 24.00 bits\n"},
+	}
+	for _, tt := range tests {
+		random = bytes.NewReader(make([]byte, 1024))
+		var stdout, stderr bytes.Buffer
+		args := append(tt.args, "--no-color")
+		if err := run(args, nil, &stdout, &stderr); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", args, err)
+			continue
+		}
+		if got := stdout.String(); got != tt.wantStdout {
+			t.Errorf("run(%q): expected stdout %q, but got %q", args, tt.wantStdout, got)
+		}
+		if got := stderr.String(); got != tt.wantStderr {
+			t.Errorf("run(%q): expected stderr %q, but got %q", args, tt.wantStderr, got)
+		}
+	}
+}
+
+func TestPassphraseAcrostic(t *testing.T) {
+	tests := []struct {
+		passphrase string
+		sep        string
+		digits     bool
+		want       string
+	}{
+		{"correct horse battery", " ", false, "chb"},
+		{"Correct-4-Horse-Battery7", "-", true, "CHB"},
+		{"correct 4 horse", " ", false, "c4h"},
+		{"éclair\nüber", "\n", false, "éü"},
+	}
+	for _, tt := range tests {
+		if got := passphraseAcrostic(tt.passphrase, tt.sep, tt.digits); got != tt.want {
+			t.Errorf("passphraseAcrostic(%q, %q, %v): expected %q, but got %q", tt.passphrase, tt.sep, tt.digits, tt.want, got)
+		}
+	}
+}
+
+func TestFilterShortWords(t *testing.T) {
+	words := []string{"a", "bb", "ccc", "ねこ", "dddd"}
+	weights := []uint64{1, 2, 3, 4, 5}
+
+	got, gotWeights := filterShortWords(words, weights, 2)
+	if want := []string{"bb", "ccc", "ねこ", "dddd"}; !slices.Equal(got, want) {
+		t.Errorf("filterShortWords: expected %q, but got %q", want, got)
+	}
+	if want := []uint64{2, 3, 4, 5}; !slices.Equal(gotWeights, want) {
+		t.Errorf("filterShortWords: expected weights %v, but got %v", want, gotWeights)
+	}
+	if got, gotWeights := filterShortWords(words, nil, 3); !slices.Equal(got, []string{"ccc", "dddd"}) || gotWeights != nil {
+		t.Errorf("filterShortWords: expected [ccc dddd] without weights, but got %q, %v", got, gotWeights)
+	}
+
+	c := &Command{Logger: Logger{Writer: io.Discard}, MinWordLen: 4}
+	if _, _, err := c.filterShortWords("test", words, weights); !errors.Is(err, ErrConstraints) {
+		t.Errorf("filterShortWords: expected %v, but got %v", ErrConstraints, err)
+	}
+}
+
+func TestCaseWords(t *testing.T) {
+	words := []string{"Apple", "apple", "Banana", "APPLE", "cherry"}
+	weights := []uint64{1, 2, 3, 4, 5}
+
+	got, gotWeights := caseWords(words, weights, CaseLower, language.Und)
+	if want := []string{"apple", "banana", "cherry"}; !slices.Equal(got, want) {
+		t.Errorf("caseWords(lower): expected %q, but got %q", want, got)
+	}
+	if want := []uint64{7, 3, 5}; !slices.Equal(gotWeights, want) {
+		t.Errorf("caseWords(lower): expected weights %v, but got %v", want, gotWeights)
+	}
+	if got, _ := caseWords(words, nil, CaseUpper, language.Und); !slices.Equal(got, []string{"APPLE", "BANANA", "CHERRY"}) {
+		t.Errorf("caseWords(upper): expected [APPLE BANANA CHERRY], but got %q", got)
+	}
+	if got, _ := caseWords(words, nil, CaseMixed, language.Und); !slices.Equal(got, []string{"apple", "banana", "cherry"}) {
+		t.Errorf("caseWords(mixed): expected [apple banana cherry], but got %q", got)
+	}
+
+	c := &Command{Logger: Logger{Writer: io.Discard}}
+	if _, _, err := c.caseWords("test", []string{"Apple", "apple"}, nil); err == nil {
+		t.Errorf("caseWords: expected a non-nil error")
+	}
+}
+
+func TestRun_caseCollidingWordlist(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+	t.Cleanup(func() { random = saved })
+	path := writeTempFile(t, "Apple\napple\nBanana\nAPPLE\n")
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-w", path, "-l", "2", "--no-color", "-e"}, "apple apple\t\t(2.00 bits)\n"},
+		{[]string{"-w", path, "-l", "2", "--case", "upper", "--no-color", "-e"}, "APPLE APPLE\t\t(2.00 bits)\n"},
+		{[]string{"-w", path, "-l", "2", "--case", "mixed", "--no-color", "-e"}, "apple apple\t\t(12.00 bits)\n"},
+	}
+	for _, tt := range tests {
+		random = bytes.NewReader(make([]byte, 1024))
+		var stdout bytes.Buffer
+		if err := run(tt.args, nil, &stdout, io.Discard); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", tt.args, err)
+			continue
+		}
+		if got := stdout.String(); got != tt.want {
+			t.Errorf("run(%q): expected %q, but got %q", tt.args, tt.want, got)
+		}
+	}
+}
+
+func TestReadCharsetSpec(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "spec")
+	spec := "# letters and digits\n+\\l\\L\\d\n-0Oo1lI\n\n&\\p{Latin}\\d\n+_\n"
+	if err := os.WriteFile(path, []byte(spec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	set, err := readCharsetSpec(path)
+	if err != nil {
+		t.Fatalf("readCharsetSpec: unexpected error: %v", err)
+	}
+	if got, want := string(set.Picker().Runes()), "23456789ABCDEFGHJKLMNPQRSTUVWXYZ_abcdefghijkmnpqrstuvwxyz"; got != want {
+		t.Errorf("readCharsetSpec: expected %q, but got %q", want, got)
+	}
+
+	for _, spec := range []string{"\uFEFF+ab\n", " +ab\t\n", "+aあ\n-あ\n+b\n"} {
+		if err := os.WriteFile(path, []byte(spec), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		set, err := readCharsetSpec(path)
+		if err != nil {
+			t.Errorf("readCharsetSpec(%q): unexpected error: %v", spec, err)
+		} else if got := string(set.Picker().Runes()); got != "ab" {
+			t.Errorf("readCharsetSpec(%q): expected %q, but got %q", spec, "ab", got)
+		}
+	}
+
+	for _, spec := range []string{"+\\d\n*a\n", "+\\q\n", "+a\n", "+ab\n\uFEFF-a\n"} {
+		if err := os.WriteFile(path, []byte(spec), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readCharsetSpec(path); err == nil {
+			t.Errorf("readCharsetSpec(%q): expected an error", spec)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("+ab\n×a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readCharsetSpec(path); err == nil || !strings.Contains(err.Error(), "'×'") {
+		t.Errorf("readCharsetSpec: expected an error about '×', but got %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+	t.Cleanup(func() { random = saved })
+
+	tests := []struct {
+		args   []string
+		random io.Reader
+		want   int
+	}{
+		{[]string{"-x"}, nil, exitOK},
+		{[]string{"--no-such-option"}, nil, exitCmdline},
+		{[]string{"-l", "0"}, nil, exitCmdline},
+		{[]string{"-x"}, failingReader{}, exitRandom},
+		{[]string{"-P", "ab", "-l", "4", "--min-unique-chars", "3"}, nil, exitConstraints},
+		{[]string{"-P", `\d`, "-l", "2", "--min-classes", "2"}, nil, exitConstraints},
+		{[]string{"-P", "a-z", "--min-digits", "1"}, nil, exitConstraints},
+		{[]string{"-P", "a-z", "-l", "4", "--insert", `1:\d`, "--min-digits", "1"}, nil, exitOK},
+		{[]string{"-P", "ぁ-ゖ", "--min-symbols", "1"}, nil, exitConstraints},
+		{[]string{"-P", `\d!`, "-l", "3", "--min-digits", "2", "--min-symbols", "2"}, nil, exitConstraints},
+		{[]string{"-P", "ab", "-l", "8", "--min-unique-chars", "2", "--retry-limit", "1"}, bytes.NewReader(make([]byte, 1024)), exitConstraints},
+		{[]string{"-w", filepath.Join(t.TempDir(), "missing.txt")}, nil, exitIO},
+		{[]string{"-P", "ab", "-l", "3", "--insert", "4:c"}, nil, exitConstraints},
+		{[]string{"-P", "ab", "--insert", "4"}, nil, exitCmdline},
+		{[]string{"-P", `a-z\l`, "--assert-no-dupes-in-charset"}, nil, exitCmdline},
+		{[]string{"-P", `\d\l`, "--weighted", "--assert-no-dupes-in-charset"}, nil, exitOK},
+		{[]string{"--derive"}, nil, exitCmdline},
+		{[]string{"-P", "ab", "--shuffle", "--max-bytes", "4"}, nil, exitCmdline},
+		{[]string{"-w", "eff-short1", "-s", "", "--max-bytes", "10"}, nil, exitCmdline},
+		{[]string{"-w", "eff-short1", "--max-bytes", "2"}, nil, exitConstraints},
+		{[]string{"-w", "eff-short1", "-s", "", "--passphrase-acrostic"}, nil, exitCmdline},
+		{[]string{"--passphrase-template", "{adj}", "-l", "3"}, nil, exitCmdline},
+		{[]string{"--config", writeTempFile(t, "unknown = 1\n")}, nil, exitCmdline},
+		{[]string{"--config", writeTempFile(t, "bits = x\n")}, nil, exitCmdline},
+		{[]string{"--config", writeTempFile(t, "bits\n")}, nil, exitCmdline},
+		{[]string{"--config", writeTempFile(t, "separator = \"unterminated\n")}, nil, exitCmdline},
+		{[]string{"--config", filepath.Join(t.TempDir(), "missing.conf")}, nil, exitIO},
+	}
+	for _, tt := range tests {
+		random = saved
+		if tt.random != nil {
+			random = tt.random
+		}
+		err := run(tt.args, nil, io.Discard, io.Discard)
+		if got := exitCode(err); got != tt.want {
+			t.Errorf("run(%q): expected exit code %v, but got %v (%v)", tt.args, tt.want, got, err)
+		}
+	}
+}
+
+func TestParseAlsoSpec(t *testing.T) {
+	tests := []struct {
+		input string
+		want  alsoSpec
+	}{
+		{"hex", alsoSpec{Hexadecimal, 0}},
+		{"passphrase:100", alsoSpec{Passphrase, 100}},
+		{"base64:64.5", alsoSpec{Base64, 64.5}},
+	}
+	for _, tt := range tests {
+		if got, err := parseAlsoSpec(tt.input); err != nil {
+			t.Errorf("parseAlsoSpec(%q): unexpected error: %v", tt.input, err)
+		} else if got != tt.want {
+			t.Errorf("parseAlsoSpec(%q): expected %v, but got %v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"", "hexadecimal", "hex:", "hex:0", "hex:abc", ":64"} {
+		if _, err := parseAlsoSpec(input); err == nil {
+			t.Errorf("parseAlsoSpec(%q): expected a non-nil error", input)
+		}
+	}
+}
+
+func TestRun_randomFailure(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+	t.Cleanup(func() { random = saved })
+
+	tests := [][]string{
+		{},
+		{"-p"},
+		{"-x"},
+		{"-u"},
+		{"--case=mixed"},
+		{"-P", "xyz", "--histogram", "5"},
+		{"-P", "xyz", "--sample", "2"},
+		{"-w", "eff-short1", "--sample", "2"},
+	}
+	for _, args := range tests {
+		random = failingReader{}
+		if err := run(args, nil, io.Discard, io.Discard); !errors.Is(err, ErrRandomSource) {
+			t.Errorf("run(%q): expected ErrRandomSource, but got %v", args, err)
+		}
+		if _, ok := random.(failingReader); !ok {
+			t.Errorf("run(%q): random was not restored", args)
+		}
+	}
+}
+
+func TestDefaultBits(t *testing.T) {
+	c := &Command{}
+	if err := c.Option("--default-bits-hex", "64", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.Option("--hex", "", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, bits, err := c.getGenerator(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if bits != 64 {
+		t.Errorf("expected 64 bits, but got %v", bits)
+	}
+
+	c.Bits = 100
+	if _, bits, err := c.getGenerator(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if bits != 100 {
+		t.Errorf("expected 100 bits, but got %v", bits)
+	}
+}
+
+func TestStrengthPreset(t *testing.T) {
+	tests := []struct {
+		preset string
+		bits   string
+		length string
+		want   float64
+	}{
+		{"weak", "", "", 64},
+		{"standard", "", "", 80},
+		{"strong", "", "", 112},
+		{"paranoid", "", "", 160},
+		{"paranoid", "90", "", 90},
+		{"paranoid", "", "5", 0},
+	}
+	for _, tt := range tests {
+		c := &Command{}
+		if err := c.Option("--strength-preset", tt.preset, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if tt.bits != "" {
+			if err := c.Option("--bits", tt.bits, true); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		if tt.length != "" {
+			if err := c.Option("--length", tt.length, true); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		c.applyStrengthPreset()
+		if c.Bits != tt.want {
+			t.Errorf("--strength-preset=%v: expected %v bits, but got %v", tt.preset, tt.want, c.Bits)
+		}
+	}
+
+	c := &Command{}
+	if err := c.Option("--strength-preset", "extreme", true); err == nil {
+		t.Errorf("--strength-preset=extreme: expected a non-nil error")
+	}
+}
+
+func TestRun_strengthPreset(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	config := writeTempFile(t, "bits = 40\n")
+
+	tests := []struct {
+		env  string
+		args []string
+		want int
+	}{
+		{"", []string{"-x", "--strength-preset", "weak"}, 16},
+		{"40", []string{"-x", "--strength-preset", "weak"}, 16},
+		{"", []string{"--config", config, "-x", "--strength-preset", "weak"}, 16},
+		{"", []string{"-x", "--strength-preset", "weak", "-b", "40"}, 10},
+		{"", []string{"-x", "--strength-preset", "weak", "-l", "4"}, 4},
+	}
+	for _, tt := range tests {
+		t.Setenv("GENPASS_BITS", tt.env)
+		var stdout bytes.Buffer
+		if err := run(tt.args, nil, &stdout, io.Discard); err != nil {
+			t.Errorf("run(%q): unexpected error: %v", tt.args, err)
+			continue
+		}
+		if got := len(strings.TrimSuffix(stdout.String(), "\n")); got != tt.want {
+			t.Errorf("GENPASS_BITS=%q run(%q): expected %v characters, but got %v", tt.env, tt.args, tt.want, got)
+		}
+	}
+}
+
+func TestCountFromOption(t *testing.T) {
+	c := &Command{}
+	if err := c.Option("--count-from", "0", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !c.Numbered || c.CountFrom != 0 {
+		t.Errorf("expected numbering from 0, but got %v from %v", c.Numbered, c.CountFrom)
+	}
+
+	for _, value := range []string{"-1", "abc", ""} {
+		if err := c.Option("--count-from", value, true); err == nil {
+			t.Errorf("--count-from=%q: expected a non-nil error", value)
+		}
+	}
+}
+
+func TestWordsOnly(t *testing.T) {
+	c := &Command{Wordlist: "eff-large", WordlistFormat: "plain", Separator: " ", Length: 4, WordsOnly: true}
+	generator, _, err := c.getGenerator()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Split(generator(), "\n"); len(lines) != 4 {
+		t.Errorf("expected 4 lines, but got %q", lines)
+	}
+
+	c = &Command{Variant: Hexadecimal, WordsOnly: true}
+	if _, _, err := c.getGenerator(); err == nil {
+		t.Errorf("expected a non-nil error for --hex")
+	}
+}
+
+func TestPassphraseLengthChars(t *testing.T) {
+	c := &Command{Wordlist: "eff-large", WordlistFormat: "plain", Separator: " ", LengthChars: 20}
+	generator, bits, err := c.getGenerator()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := 2 * math.Log2(7776); math.Abs(bits-want) > 1e-9 {
+		t.Errorf("expected %v bits, but got %v", want, bits)
+	}
+	for range 20 {
+		if s := generator(); s == "" || utf8.RuneCountInString(s) > 20 {
+			t.Errorf("unexpected passphrase %q", s)
+		}
+	}
+
+	c.LengthChars = 2
+	if _, _, err := c.getGenerator(); err == nil {
+		t.Errorf("expected a non-nil error for a cap shorter than every word")
+	}
+
+	c.LengthChars, c.Length = 20, 4
+	if _, _, err := c.getGenerator(); err == nil {
+		t.Errorf("expected a non-nil error with --length")
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	tests := []struct {
+		input  string
+		cols   uint
+		escape bool
+		want   string
+	}{
+		{"", 4, false, ""},
+		{"abcd", 4, false, "abcd"},
+		{"abcdefghij", 4, false, "abcd\nefgh\nij"},
+		{"abcdefgh", 4, false, "abcd\nefgh"},
+		{"あいうえお", 2, false, "あい\nうえ\nお"},
+		{"abc\ndefgh", 3, false, "abc\ndef\ngh"},
+		{"あいう", 12, true, `\u3042\u3044` + "\n" + `\u3046`},
+		{"aあb", 8, true, `a\u3042b`},
+		{"aあb", 4, true, "a\n" + `\u3042` + "\nb"},
+		{"a\\b\nc", 2, true, "a\n" + `\\` + "\nb\nc"},
+	}
+
+	for _, tt := range tests {
+		if got := wrapLine(tt.input, tt.cols, tt.escape); got != tt.want {
+			t.Errorf("wrapLine(%q, %v, %v): expected %q, but got %q", tt.input, tt.cols, tt.escape, tt.want, got)
+		}
+	}
+}
+
+func TestRiskyCharsReport(t *testing.T) {
+	tests := []struct {
+		charset string
+		want    string
+	}{
+		{`a-zA-Z0-9`, ""},
+		{`a-z$,`, `shells: '$'; CSV: ','`},
+		{`a-z "%`, `shells: ' ' '"'; CSV: '"'; URLs: ' ' '%'`},
+	}
+
+	for _, tt := range tests {
+		set, err := runeset.Parse(tt.charset)
+		if err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", tt.charset, err)
+		}
+		if got := riskyCharsReport(set.Picker()); got != tt.want {
+			t.Errorf("riskyCharsReport(%q): expected %q, but got %q", tt.charset, tt.want, got)
+		}
+	}
+}
+
+func TestEscapeNonASCII(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"", ""},
+		{"abc ~!", "abc ~!"},
+		{`a\b`, `a\\b`},
+		{"caf\u00E9", `caf\u00E9`},
+		{"\U0001F600\t", `\U0001F600\u0009`},
+		{"foo\nbar", "foo\nbar"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeNonASCII(tt.input); got != tt.want {
+			t.Errorf("escapeNonASCII(%q): expected %q, but got %q", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestCheckPrintableASCII(t *testing.T) {
+	tests := []struct {
+		input        string
+		allowNewline bool
+		ok           bool
+	}{
+		{"", false, true},
+		{" !azAZ09~", false, true},
+		{"abc\x7F", false, false},
+		{"abc\tdef", false, false},
+		{"caf\u00E9", false, false},
+		{"foo\nbar", false, false},
+		{"foo\nbar", true, true},
+	}
+
+	for _, tt := range tests {
+		if err := checkPrintableASCII(tt.input, tt.allowNewline); (err == nil) != tt.ok {
+			t.Errorf("checkPrintableASCII(%q, %v): unexpected result: %v", tt.input, tt.allowNewline, err)
+		}
+	}
+}
+
+func TestVariants(t *testing.T) {
+	for i, v := range variants {
+		c := &Command{Wordlist: "eff-large", WordlistFormat: "plain", Separator: " "}
+		if err := c.configOption("variant", v.Name); err != nil {
+			t.Fatalf("%v: unexpected error: %v", v.Name, err)
+		}
+		if c.Variant != Variant(i) {
+			t.Errorf("%v: expected variant %v, but got %v", v.Name, i, c.Variant)
+		}
+		generator, bits, err := c.getGenerator()
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", v.Name, err)
+		}
+		if bits < float64(v.DefaultBits) {
+			t.Errorf("%v: expected at least %v bits, but got %v", v.Name, v.DefaultBits, bits)
+		}
+		if generator() == "" {
+			t.Errorf("%v: generated an empty string", v.Name)
+		}
+	}
+}
+
+func TestPrintSample(t *testing.T) {
+	c := &Command{Sample: 50, Wordlist: "eff-short1"}
+	var buf bytes.Buffer
+	if err := c.printSample(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 50 {
+		t.Errorf("expected 50 lines, but got %v", len(lines))
+	}
+	for _, line := range lines {
+		if !slices.Contains(wordlists.EFFShort1, line) {
+			t.Errorf("unexpected word %q", line)
+		}
+	}
+
+	c = &Command{Sample: 50}
+	if err := c.Option("--password-with", `\d`, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	buf.Reset()
+	if err := c.printSample(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if len(line) != 1 || line[0] < '0' || line[0] > '9' {
+			t.Errorf("unexpected character %q", line)
+		}
+	}
+
+	c = &Command{Sample: 50, Wordlist: "eff-short1", Case: CaseUpper, MinWordLen: 5}
+	buf.Reset()
+	if err := c.printSample(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+		if !slices.Contains(wordlists.EFFShort1, strings.ToLower(line)) || line != strings.ToUpper(line) || len(line) < 5 {
+			t.Errorf("unexpected word %q for --case=upper and --passphrase-min-word-len-in-output=5", line)
+		}
+	}
+
+	c = &Command{Sample: 1, Variant: Hexadecimal}
+	if err := c.printSample(&buf); err == nil {
+		t.Errorf("expected a non-nil error for --hex")
+	}
+
+	c = &Command{Sample: 1, Sentence: true}
+	if err := c.printSample(&buf); err == nil {
+		t.Errorf("expected a non-nil error for --passphrase-from-sentence")
+	}
+}
+
+func TestBitsColor(t *testing.T) {
+	tests := []struct {
+		bits float64
+		want colorterm.EscapeCode
+	}{
+		{0, colorterm.FgRed},
+		{63.99, colorterm.FgRed},
+		{64, colorterm.FgYellow},
+		{100, colorterm.FgYellow},
+		{100.01, colorterm.FgGreen},
+		{256, colorterm.FgGreen},
+	}
+	for _, tt := range tests {
+		if got := bitsColor(tt.bits); got != tt.want {
+			t.Errorf("bitsColor(%v): expected %q, but got %q", tt.bits, string(tt.want), string(got))
+		}
+	}
+}
+
+func TestCrackTime(t *testing.T) {
+	tests := []struct {
+		bits float64
+		rate float64
+		want string
+	}{
+		{1, 10, "<1 second"},
+		{2, 1, "~2 seconds"},
+		{7, 1, "~1 minute"},
+		{13, 1, "~1 hour"},
+		{40, 1e10, "~55 seconds"},
+		{52, 1e10, "~3 days"},
+		{60, 1e10, "~2 years"},
+		{80, 1e10, "~2e+06 years"},
+	}
+
+	for _, tt := range tests {
+		if got := crackTime(tt.bits, tt.rate); got != tt.want {
+			t.Errorf("crackTime(%v, %v): expected %q, but got %q", tt.bits, tt.rate, tt.want, got)
+		}
+	}
+}
+
+func TestBitsOption(t *testing.T) {
+	c := &Command{Variant: Hexadecimal}
+	if err := c.Option("--bits", "82.5", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, bits, err := c.getGenerator(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if bits != 84 {
+		t.Errorf("expected 84 bits, but got %v", bits)
+	}
+
+	for _, value := range []string{"0", "-1", "NaN", "Inf", "abc", ""} {
+		if err := c.Option("--bits", value, true); err == nil {
+			t.Errorf("--bits=%q: expected a non-nil error", value)
+		}
+	}
+}
+
+func TestRun_split(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	config := writeTempFile(t, "split = 2\n")
+
+	for _, args := range [][]string{
+		{"--config", config, "-p"},
+		{"-x", "--split", "2", "--hash", "bcrypt"},
+		{"-x", "--split", "2", "--ensure-printable-ascii"},
+		{"--config", config, "-x", "-c", "2", "--unique"},
+		{"--config", config, "-x", "--count-from", "1"},
+		{"--config", config, "-x", "-e"},
+	} {
+		if err := run(args, nil, io.Discard, io.Discard); err == nil || !strings.Contains(err.Error(), "--split") {
+			t.Errorf("run(%q): expected an error about --split, but got %v", args, err)
+		}
+	}
+
+	args := []string{"-x", "-l", "8", "--split", "2", "--bits-summary", "--no-color"}
+	var stdout, stderr bytes.Buffer
+	if err := run(args, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run(%q): unexpected error: %v", args, err)
+	}
+	lines := strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "share 1/2\t") || !strings.HasPrefix(lines[1], "share 2/2\t") {
+		t.Errorf("run(%q): expected only the 2 shares, but got %q", args, stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "32.00 bits") {
+		t.Errorf("run(%q): expected a strength summary, but got %q", args, stderr.String())
+	}
+
+	args = []string{"-x", "-l", "8", "-c", "2", "--split", "2"}
+	stdout.Reset()
+	if err := run(args, nil, &stdout, io.Discard); err != nil {
+		t.Fatalf("run(%q): unexpected error: %v", args, err)
+	}
+	lines = strings.Split(strings.TrimSuffix(stdout.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("run(%q): expected 4 shares, but got %q", args, stdout.String())
+	}
+	for i, line := range lines {
+		if want := fmt.Sprintf("secret %d share %d/2\t", i/2+1, i%2+1); !strings.HasPrefix(line, want) {
+			t.Errorf("run(%q): expected line %d to start with %q, but got %q", args, i+1, want, line)
+		}
+	}
+}
+
+func TestRun_showEntropyBytes(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	saved := random
+	t.Cleanup(func() { random = saved })
+	random = bytes.NewReader([]byte{0x12, 0x34, 0xab, 0xcd})
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-x", "-l", "4", "-c", "2", "--show-entropy-bytes"}
+	if err := run(args, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run(%q): unexpected error: %v", args, err)
+	}
+	if got, want := stdout.String(), "1234\nabcd\n"; got != want {
+		t.Errorf("run(%q): expected %q, but got %q", args, want, got)
+	}
+	for _, want := range []string{"warning: --show-entropy-bytes", "random bytes: 1234\n", "random bytes: abcd\n"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("run(%q): expected %q on the standard error, but got %q", args, want, stderr.String())
+		}
+	}
+}
+
+func TestGetPatternGenerator(t *testing.T) {
+	saved := random
+	t.Cleanup(func() { random = saved })
+
+	tests := []struct {
+		args []string
+		want string
+		bits string
+	}{
+		{[]string{"--pattern", "adj,noun", "-l", "3"}, "able acorn able", "24.65"},
+		{[]string{"--pattern", "adj,noun,verb"}, "able acorn accept able acorn accept able acorn accept able", "81.96"},
+	}
+	for _, tt := range tests {
+		random = bytes.NewReader(make([]byte, 1024))
+		c := &Command{Logger: Logger{Writer: &bytes.Buffer{}}, Separator: " "}
+		if _, err := options.Parse(c, tt.args); err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", tt.args, err)
+		}
+		generator, bits, err := c.getPassphraseGenerator(80)
+		if err != nil {
+			t.Fatalf("getPassphraseGenerator(%q): unexpected error: %v", tt.args, err)
+		}
+		if got := generator(); got != tt.want {
+			t.Errorf("getPassphraseGenerator(%q): expected %q, but got %q", tt.args, tt.want, got)
+		}
+		if got := fmt.Sprintf("%.2f", bits); got != tt.bits {
+			t.Errorf("getPassphraseGenerator(%q): expected %v bits, but got %v", tt.args, tt.bits, got)
+		}
+	}
+
+	c := &Command{Logger: Logger{Writer: &bytes.Buffer{}}, Separator: " "}
+	args := []string{"--pattern", "adj,nown"}
+	if _, err := options.Parse(c, args); err != nil {
+		t.Fatalf("Parse(%q): unexpected error: %v", args, err)
+	}
+	if _, _, err := c.getPassphraseGenerator(80); err == nil {
+		t.Errorf("getPassphraseGenerator(%q): expected an error", args)
+	}
+	if _, err := options.Parse(&Command{}, []string{"--pattern", "adj,,noun"}); err == nil {
+		t.Errorf("Parse(%q): expected an error", "--pattern=adj,,noun")
+	}
+}
+
+func TestPrintHistogram(t *testing.T) {
+	c := &Command{}
+	args := []string{"-P", "0-9", "--histogram", "10000"}
+	if _, err := options.Parse(c, args); err != nil {
+		t.Fatalf("Parse(%q): unexpected error: %v", args, err)
+	}
+	var b bytes.Buffer
+	c.Stdout = &b
+	if err := c.printHistogram(); err != nil {
+		t.Fatalf("printHistogram(%q): unexpected error: %v", args, err)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != 12 || lines[0] != "CHAR\tCOUNT\tRATIO" || !strings.HasPrefix(lines[11], "chi-squared: ") {
+		t.Fatalf("printHistogram(%q): unexpected output %q", args, b.String())
+	}
+	var total int
+	for _, line := range lines[1:11] {
+		var char string
+		var count int
+		var ratio float64
+		if _, err := fmt.Sscanf(line, "%s\t%d\t%f", &char, &count, &ratio); err != nil {
+			t.Fatalf("printHistogram(%q): malformed line %q: %v", args, line, err)
+		}
+		total += count
+	}
+	if total != 10000 {
+		t.Errorf("printHistogram(%q): expected the counts to add up to 10000, but got %v", args, total)
+	}
+
+	for _, args := range [][]string{
+		{"--histogram", "10"},
+		{"-P", `\u0100-\u0300`, "--histogram", "10"},
+	} {
+		c := &Command{}
+		if _, err := options.Parse(c, args); err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", args, err)
+		}
+		c.Stdout = io.Discard
+		if err := c.printHistogram(); err == nil {
+			t.Errorf("printHistogram(%q): expected an error", args)
+		}
+	}
+}
+
+type closingWriter struct {
+	bytes.Buffer
+	lines int
+}
+
+func (w *closingWriter) Write(p []byte) (int, error) {
+	if w.lines == 0 {
+		return 0, syscall.EPIPE
+	}
+	w.lines--
+	return w.Buffer.Write(p)
+}
+
+func TestRun_countZero(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	stdout := &closingWriter{lines: 5}
+	args := []string{"-x", "-l", "4", "-c", "0"}
+	if err := run(args, nil, stdout, io.Discard); err != nil {
+		t.Fatalf("run(%q): unexpected error: %v", args, err)
+	}
+	if got := strings.Count(stdout.String(), "\n"); got != 5 {
+		t.Errorf("run(%q): expected 5 lines before the output was closed, but got %v", args, got)
+	}
+}
+
+func TestRun_templateWarning(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	var stderr bytes.Buffer
+	args := []string{"--passphrase-template", "{adj} {noun} {number}"}
+	if err := run(args, nil, io.Discard, &stderr); err != nil {
+		t.Fatalf("run(%q): unexpected error: %v", args, err)
+	}
+	if !strings.Contains(stderr.String(), "less than the default") {
+		t.Errorf("run(%q): expected a warning, but got %q", args, stderr.String())
+	}
+
+	stderr.Reset()
+	args = []string{"--passphrase-template", strings.Repeat("{eff-large} ", 7)}
+	if err := run(args, nil, io.Discard, &stderr); err != nil {
+		t.Fatalf("run(%q): unexpected error: %v", args, err)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("run(%q): expected no warning, but got %q", args, stderr.String())
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const (
+	progressStride   = 1024
+	progressInterval = 200 * time.Millisecond
+)
+
+type progress struct {
+	w     io.Writer
+	total uint
+	last  time.Time
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+func (c *Command) newProgress() *progress {
+	if !c.Progress || c.Quiet || !isTerminal(c.Writer) {
+		return nil
+	}
+	return &progress{w: c.Writer, total: c.Count, last: time.Now()}
+}
+
+func (p *progress) update(n uint) {
+	if p == nil || n%progressStride != 0 {
+		return
+	}
+	if now := time.Now(); now.Sub(p.last) >= progressInterval {
+		p.last = now
+		p.print(n)
+	}
+}
+
+func (p *progress) print(n uint) {
+	if p.total == 0 {
+		fmt.Fprintf(p.w, "\r%v: %d generated", NAME, n)
+	} else {
+		fmt.Fprintf(p.w, "\r%v: %d/%d generated (%d%%)", NAME, n, p.total, uint64(n)*100/uint64(p.total))
+	}
+}
+
+func (p *progress) done() {
+	if p == nil {
+		return
+	}
+	fmt.Fprint(p.w, "\r\x1b[K")
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	var b bytes.Buffer
+	p := &progress{w: &b, total: 4096}
+
+	p.update(1)
+	if got := b.String(); got != "" {
+		t.Errorf("update(1): expected no output, but got %q", got)
+	}
+	p.update(2048)
+	if got, want := b.String(), "\r"+NAME+": 2048/4096 generated (50%)"; got != want {
+		t.Errorf("update(2048): expected %q, but got %q", want, got)
+	}
+	b.Reset()
+	p.last = time.Now()
+	p.update(3072)
+	if got := b.String(); got != "" {
+		t.Errorf("update(3072) right after an update: expected no output, but got %q", got)
+	}
+	p.done()
+	if got, want := b.String(), "\r\x1b[K"; got != want {
+		t.Errorf("done(): expected %q, but got %q", want, got)
+	}
+
+	var nilProgress *progress
+	nilProgress.update(progressStride)
+	nilProgress.done()
+}
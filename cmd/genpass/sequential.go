@@ -0,0 +1,78 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"slices"
+	"unicode"
+
+	"github.com/cions/genpass/internal/runeset"
+)
+
+const sequentialRun = 3
+
+var sequences = []string{
+	"0123456789",
+	"abcdefghijklmnopqrstuvwxyz",
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+}
+
+var sequentialRuns = func() map[string]bool {
+	runs := make(map[string]bool)
+	for _, seq := range sequences {
+		forward := []rune(seq)
+		backward := slices.Clone(forward)
+		slices.Reverse(backward)
+		for i := 0; i+sequentialRun <= len(forward); i++ {
+			runs[string(forward[i:i+sequentialRun])] = true
+			runs[string(backward[i:i+sequentialRun])] = true
+		}
+	}
+	return runs
+}()
+
+func hasSequentialRun(s string) bool {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	for i := 0; i+sequentialRun <= len(runes); i++ {
+		if sequentialRuns[string(runes[i:i+sequentialRun])] {
+			return true
+		}
+	}
+	return false
+}
+
+// positions holds the set each position is drawn from.
+func noSequentialProbability(positions []*runeset.RuneSet) float64 {
+	pickers := make(map[*runeset.RuneSet]*runeset.Picker)
+	for _, set := range positions {
+		if pickers[set] == nil {
+			pickers[set] = set.Picker()
+		}
+	}
+
+	var p float64
+	for i := 0; i+sequentialRun <= len(positions); i++ {
+		for run := range sequentialRuns {
+			q := float64(1)
+			for j, r := range []rune(run) {
+				picker := pickers[positions[i+j]]
+				var variants float64
+				if picker.Contains(r) {
+					variants++
+				}
+				if upper := unicode.ToUpper(r); upper != r && picker.Contains(upper) {
+					variants++
+				}
+				q *= variants / float64(picker.Size())
+			}
+			p += q
+		}
+	}
+	return max(1-p, 0)
+}
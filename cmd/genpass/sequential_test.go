@@ -0,0 +1,60 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/cions/genpass/internal/runeset"
+)
+
+func TestHasSequentialRun(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"", false},
+		{"ab", false},
+		{"abd", false},
+		{"abc", true},
+		{"xCBa", true},
+		{"a123", true},
+		{"9876", true},
+		{"x90", false},
+		{"Qwe!", true},
+		{"lkj", true},
+		{"zab", false},
+		{"aあいう", false},
+	}
+
+	for _, tt := range tests {
+		if got := hasSequentialRun(tt.input); got != tt.want {
+			t.Errorf("hasSequentialRun(%q): expected %v, but got %v", tt.input, tt.want, got)
+		}
+	}
+}
+
+func TestNoSequentialProbability(t *testing.T) {
+	set, err := runeset.Parse(`\d`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := noSequentialProbability(testPositions(&set, nil, 2)); got != 1 {
+		t.Errorf("noSequentialProbability(2): expected 1, but got %v", got)
+	}
+	if got, want := noSequentialProbability(testPositions(&set, nil, 4)), 1-2*16.0/1000; math.Abs(got-want) > 1e-12 {
+		t.Errorf("noSequentialProbability(4): expected %v, but got %v", want, got)
+	}
+
+	// With the first digit fixed to 5, the first window is sequential only
+	// as 567 or 543.
+	first, err := runeset.Parse(`5`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := noSequentialProbability(testPositions(&set, &first, 4)), 1-2.0/100-16.0/1000; math.Abs(got-want) > 1e-12 {
+		t.Errorf("noSequentialProbability(5, 4): expected %v, but got %v", want, got)
+	}
+}
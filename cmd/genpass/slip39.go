@@ -0,0 +1,109 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cions/genpass/internal/wordlists"
+)
+
+var rs1024Generator = [10]uint32{
+	0x00E0E040, 0x01C1C080, 0x03838100, 0x07070200, 0x0E0E0009,
+	0x1C0C2412, 0x38086C24, 0x3090FC48, 0x21B1F890, 0x03F3F120,
+}
+
+func rs1024Polymod(values []int) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xFFFFF)<<10 ^ uint32(v)
+		for i, gen := range rs1024Generator {
+			if (b>>i)&1 != 0 {
+				chk ^= gen
+			}
+		}
+	}
+	return chk
+}
+
+func rs1024Checksum(customization string, data []int) []int {
+	values := make([]int, 0, len(customization)+len(data)+3)
+	for _, ch := range []byte(customization) {
+		values = append(values, int(ch))
+	}
+	values = append(values, data...)
+	values = append(values, 0, 0, 0)
+	polymod := rs1024Polymod(values) ^ 1
+	return []int{
+		int(polymod>>20) & 1023,
+		int(polymod>>10) & 1023,
+		int(polymod) & 1023,
+	}
+}
+
+type bitWriter struct {
+	words []int
+	acc   uint32
+	nbits int
+}
+
+func (w *bitWriter) Write(value uint32, nbits int) {
+	for i := nbits - 1; i >= 0; i-- {
+		w.acc = w.acc<<1 | (value>>i)&1
+		w.nbits++
+		if w.nbits == 10 {
+			w.words = append(w.words, int(w.acc))
+			w.acc, w.nbits = 0, 0
+		}
+	}
+}
+
+func slip39ShareWords(secretBytes int) (uint, error) {
+	switch secretBytes {
+	case 16:
+		return 20, nil
+	case 32:
+		return 33, nil
+	default:
+		return 0, fmt.Errorf("SLIP39 shares can encode only 128-bit or 256-bit secrets")
+	}
+}
+
+func newSLIP39ShareGenerator(secretBytes int, sep string) Generator {
+	if _, err := slip39ShareWords(secretBytes); err != nil {
+		panic("newSLIP39ShareGenerator: " + err.Error())
+	}
+	return func() string {
+		buf := make([]byte, 2+secretBytes)
+		if _, err := io.ReadFull(random, buf); err != nil {
+			panic(fmt.Sprintf("crypto/rand: %v", err))
+		}
+		identifier := uint32(buf[0])<<8 | uint32(buf[1])
+		secret := buf[2:]
+
+		var w bitWriter
+		w.Write(identifier&0x7FFF, 15) // identifier
+		w.Write(0, 1)                  // extendable backup flag
+		w.Write(0, 4)                  // iteration exponent
+		w.Write(0, 4)                  // group index
+		w.Write(0, 4)                  // group threshold - 1
+		w.Write(0, 4)                  // group count - 1
+		w.Write(0, 4)                  // member index
+		w.Write(0, 4)                  // member threshold - 1
+		w.Write(0, (10-8*len(secret)%10)%10)
+		for _, b := range secret {
+			w.Write(uint32(b), 8)
+		}
+		data := append(w.words, rs1024Checksum("shamir", w.words)...)
+
+		words := make([]string, len(data))
+		for i, index := range data {
+			words[i] = wordlists.SLIP39[index]
+		}
+		return strings.Join(words, sep)
+	}
+}
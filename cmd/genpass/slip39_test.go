@@ -0,0 +1,54 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/cions/genpass/internal/wordlists"
+)
+
+func slip39Indices(t *testing.T, mnemonic string) []int {
+	t.Helper()
+
+	var values []int
+	for _, ch := range []byte("shamir") {
+		values = append(values, int(ch))
+	}
+	for _, word := range strings.Fields(mnemonic) {
+		index := slices.Index(wordlists.SLIP39, word)
+		if index < 0 {
+			t.Fatalf("unknown word %q", word)
+		}
+		values = append(values, index)
+	}
+	return values
+}
+
+func TestRS1024Polymod(t *testing.T) {
+	mnemonic := "duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision keyboard"
+	if got := rs1024Polymod(slip39Indices(t, mnemonic)); got != 1 {
+		t.Errorf("expected a valid checksum, but got polymod %#x", got)
+	}
+
+	invalid := "duckling enlarge academic academic agency result length solution fridge kidney coal piece deal husband erode duke ajar critical decision kidney"
+	if got := rs1024Polymod(slip39Indices(t, invalid)); got == 1 {
+		t.Errorf("expected an invalid checksum")
+	}
+}
+
+func TestSLIP39ShareGenerator(t *testing.T) {
+	for _, secretBytes := range []int{16, 32} {
+		nwords, _ := slip39ShareWords(secretBytes)
+		mnemonic := newSLIP39ShareGenerator(secretBytes, " ")()
+		if got := uint(len(strings.Fields(mnemonic))); got != nwords {
+			t.Errorf("expected %v words, but got %v", nwords, got)
+		}
+		if got := rs1024Polymod(slip39Indices(t, mnemonic)); got != 1 {
+			t.Errorf("%q: expected a valid checksum, but got polymod %#x", mnemonic, got)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"unicode/utf8"
+)
+
+var lengthPercentiles = []int{10, 25, 50, 75, 90}
+
+type wordlistStats struct {
+	Words             int            `json:"words"`
+	BitsPerWord       float64        `json:"bits_per_word"`
+	MinLength         int            `json:"min_length"`
+	MaxLength         int            `json:"max_length"`
+	MeanLength        float64        `json:"mean_length"`
+	LengthPercentiles map[string]int `json:"length_percentiles"`
+	PrefixFree        bool           `json:"prefix_free"`
+}
+
+func analyzeWordlist(words []string, weights []uint64) wordlistStats {
+	stats := wordlistStats{
+		Words:             len(words),
+		LengthPercentiles: make(map[string]int),
+		PrefixFree:        len(prefixPairs(words)) == 0,
+	}
+	if len(words) == 0 {
+		return stats
+	}
+	if weights != nil {
+		stats.BitsPerWord = shannonEntropy(weights)
+	} else {
+		stats.BitsPerWord = math.Log2(float64(len(words)))
+	}
+
+	lengths := make([]int, len(words))
+	var total int
+	for i, word := range words {
+		lengths[i] = utf8.RuneCountInString(word)
+		total += lengths[i]
+	}
+	slices.Sort(lengths)
+	stats.MinLength = lengths[0]
+	stats.MaxLength = lengths[len(lengths)-1]
+	stats.MeanLength = float64(total) / float64(len(lengths))
+	for _, p := range lengthPercentiles {
+		rank := (p*len(lengths) + 99) / 100
+		stats.LengthPercentiles[fmt.Sprintf("p%d", p)] = lengths[max(rank, 1)-1]
+	}
+	return stats
+}
+
+func (c *Command) printWordlistStats() error {
+	words, weights, err := c.getWordlist(c.Wordlist)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(analyzeWordlist(words, weights), "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.Stdout, string(data))
+	return err
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestAnalyzeWordlist(t *testing.T) {
+	stats := analyzeWordlist([]string{"a", "bb", "ccc", "dddd"}, nil)
+	if stats.Words != 4 || stats.BitsPerWord != 2 || stats.MinLength != 1 || stats.MaxLength != 4 || stats.MeanLength != 2.5 || !stats.PrefixFree {
+		t.Errorf("unexpected result %+v", stats)
+	}
+	want := map[string]int{"p10": 1, "p25": 1, "p50": 2, "p75": 3, "p90": 4}
+	if !maps.Equal(stats.LengthPercentiles, want) {
+		t.Errorf("expected percentiles %v, but got %v", want, stats.LengthPercentiles)
+	}
+
+	if stats := analyzeWordlist([]string{"foo", "foobar"}, nil); stats.PrefixFree {
+		t.Errorf("expected a wordlist that is not prefix-free")
+	}
+	if stats := analyzeWordlist([]string{"foo", "bar"}, []uint64{1, 1}); stats.BitsPerWord != 1 {
+		t.Errorf("expected 1 bit per word, but got %v", stats.BitsPerWord)
+	}
+}
+
+func TestPrintWordlistStats(t *testing.T) {
+	var stdout bytes.Buffer
+	c := &Command{Stdout: &stdout, Wordlist: "eff-short1", WordlistFormat: "plain"}
+	if err := c.printWordlistStats(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &fields); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	want := []string{"bits_per_word", "length_percentiles", "max_length", "mean_length", "min_length", "prefix_free", "words"}
+	if got := slices.Sorted(maps.Keys(fields)); !slices.Equal(got, want) {
+		t.Errorf("expected fields %v, but got %v", want, got)
+	}
+	if fields["words"] != float64(1296) || fields["prefix_free"] != true {
+		t.Errorf("unexpected stats %v", fields)
+	}
+}
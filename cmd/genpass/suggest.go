@@ -0,0 +1,48 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+var builtinWordlists = []string{
+	"eff-large",
+	"eff-short1",
+	"eff-short2",
+	"bip39",
+	"slip39",
+	"adjectives",
+	"nouns",
+	"verbs",
+}
+
+const maxSuggestionDistance = 2
+
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range s {
+		cur[0] = i + 1
+		for j := range t {
+			cost := 1
+			if s[i] == t[j] {
+				cost = 0
+			}
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(t)]
+}
+
+func suggestWordlist(name string) (string, bool) {
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, candidate := range builtinWordlists {
+		if d := levenshtein(name, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, best != ""
+}
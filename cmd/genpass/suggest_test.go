@@ -0,0 +1,72 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cions/go-options"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"eff-larg", "eff-large", 1},
+		{"bip39", "bip39", 0},
+		{"ねこ", "ねご", 1},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q): expected %v, but got %v", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
+
+func TestSuggestWordlist(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"eff-larg", "eff-large", true},
+		{"eff_large", "eff-large", true},
+		{"eff-short", "eff-short1", true},
+		{"bip-39", "bip39", true},
+		{"noun", "nouns", true},
+		{"words.txt", "", false},
+		{"/usr/share/dict/words", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := suggestWordlist(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("suggestWordlist(%q): expected (%q, %v), but got (%q, %v)", tt.name, tt.want, tt.ok, got, ok)
+		}
+	}
+}
+
+func TestGetWordlist_suggestion(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	var c Command
+	_, _, err := c.getWordlist("eff-larg")
+	if !errors.Is(err, options.ErrCmdline) || !strings.Contains(err.Error(), `did you mean "eff-large"?`) {
+		t.Errorf("getWordlist(%q): expected a suggestion, but got %v", "eff-larg", err)
+	}
+
+	_, _, err = c.getWordlist(filepath.Join("missing", "words.txt"))
+	if err == nil || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("getWordlist(%q): expected a plain error, but got %v", "missing/words.txt", err)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cions/genpass/internal/runeset"
+	"github.com/cions/genpass/internal/wordlists"
+)
+
+type templatePart struct {
+	Literal     string
+	Placeholder string
+}
+
+type templateSlot struct {
+	Generator Generator
+	Bits      float64
+}
+
+var templateAliases = map[string]string{
+	"adj":  "adjectives",
+	"noun": "nouns",
+	"verb": "verbs",
+}
+
+func parseTemplate(tmpl string) ([]templatePart, error) {
+	var parts []templatePart
+	var literal strings.Builder
+	for i := 0; i < len(tmpl); i++ {
+		switch {
+		case strings.HasPrefix(tmpl[i:], "{{"), strings.HasPrefix(tmpl[i:], "}}"):
+			literal.WriteByte(tmpl[i])
+			i++
+		case tmpl[i] == '{':
+			end := strings.IndexByte(tmpl[i:], '}')
+			if end < 0 {
+				return nil, errors.New("unterminated placeholder")
+			}
+			name := tmpl[i+1 : i+end]
+			if alias, ok := templateAliases[name]; ok {
+				name = alias
+			}
+			switch {
+			case name == "":
+				return nil, errors.New("empty placeholder")
+			case name == "number", name == "digit", name == "symbol", name == "adverb", name == "determiner":
+			case !slices.Contains(builtinWordlists, name):
+				return nil, fmt.Errorf("unknown placeholder {%v}", tmpl[i+1:i+end])
+			}
+			if literal.Len() != 0 {
+				parts = append(parts, templatePart{Literal: literal.String()})
+				literal.Reset()
+			}
+			parts = append(parts, templatePart{Placeholder: name})
+			i += end
+		case tmpl[i] == '}':
+			return nil, errors.New("unmatched '}' (use '}}' for a literal '}')")
+		default:
+			literal.WriteByte(tmpl[i])
+		}
+	}
+	if literal.Len() != 0 {
+		parts = append(parts, templatePart{Literal: literal.String()})
+	}
+	if len(parts) == 0 {
+		return nil, errors.New("empty template")
+	}
+	return parts, nil
+}
+
+func numberSlot(n int) templateSlot {
+	return templateSlot{func() string {
+		return strconv.Itoa(randomInt(n))
+	}, math.Log2(float64(n))}
+}
+
+func (c *Command) getTemplateSlot(name string) (templateSlot, error) {
+	switch name {
+	case "number":
+		return numberSlot(100), nil
+	case "digit":
+		return numberSlot(10), nil
+	case "symbol":
+		set, err := runeset.Parse(`\s`)
+		if err != nil {
+			return templateSlot{}, err
+		}
+		picker := set.Picker()
+		return templateSlot{func() string {
+			return picker.RandomStringFrom(random, 1)
+		}, picker.Bits()}, nil
+	case "adverb":
+		return c.wordSlot(name, wordlists.Adverbs)
+	case "determiner":
+		return c.wordSlot(name, wordlists.Determiners)
+	}
+
+	words, _, err := c.getWordlist(name)
+	if err != nil {
+		return templateSlot{}, err
+	}
+	return c.wordSlot(name, words)
+}
+
+func (c *Command) wordSlot(name string, words []string) (templateSlot, error) {
+	words, _, err := c.filterShortWords(name, words, nil)
+	if err != nil {
+		return templateSlot{}, err
+	}
+	wordlist := newWordlist(words, nil)
+	bits := math.Log2(float64(len(words)))
+	if c.Case == CaseMixed {
+		minCased := uint(math.MaxUint)
+		for _, word := range words {
+			minCased = min(minCased, casedLetters(word))
+		}
+		bits += float64(minCased)
+	}
+	return templateSlot{func() string {
+		return c.Case.apply(wordlist.Random(), c.Locale)
+	}, bits}, nil
+}
+
+func (c *Command) newTemplateGenerator(parts []templatePart) (Generator, float64, error) {
+	slots := make([]templateSlot, len(parts))
+	var bits float64
+	for i, part := range parts {
+		if part.Placeholder == "" {
+			continue
+		}
+		slot, err := c.getTemplateSlot(part.Placeholder)
+		if err != nil {
+			return nil, 0, err
+		}
+		slots[i] = slot
+		bits += slot.Bits
+	}
+	c.Debugf("template slots: %d", len(parts))
+
+	generator := func() string {
+		var b strings.Builder
+		for i, part := range parts {
+			if part.Placeholder == "" {
+				b.WriteString(part.Literal)
+			} else {
+				b.WriteString(slots[i].Generator())
+			}
+		}
+		return b.String()
+	}
+	return generator, bits, nil
+}
+
+func (c *Command) getTemplateGenerator(defaultBits uint) (Generator, float64, error) {
+	generator, bits, err := c.newTemplateGenerator(c.Template)
+	if err != nil {
+		return nil, 0, fmt.Errorf("--passphrase-template: %w", err)
+	}
+	generator, bits = c.appendFromCharset(generator, bits)
+	if c.Bits == 0 && bits < float64(defaultBits) {
+		c.Warnf("--passphrase-template: generated strings have only %.2f bits of strength, less than the default of %d bits", bits, defaultBits)
+	}
+	return generator, bits, nil
+}
+
+const sentenceTemplate = "{determiner} {adj} {noun} will {verb} {adverb}."
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func (c *Command) getSentenceGenerator(defaultBits uint) (Generator, float64, error) {
+	parts, err := parseTemplate(sentenceTemplate)
+	if err != nil {
+		return nil, 0, err
+	}
+	sentence, sentenceBits, err := c.newTemplateGenerator(parts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("--passphrase-from-sentence: %w", err)
+	}
+
+	nsentences, target := c.lengthAndTarget(defaultBits)
+	for float64(nsentences)*sentenceBits < target {
+		nsentences++
+	}
+	c.Debugf("strength per sentence: %.2f bits", sentenceBits)
+	c.Debugf("sentences per passphrase: %d", nsentences)
+
+	generator := func() string {
+		sentences := make([]string, nsentences)
+		for i := range sentences {
+			// The words are already cased, but the fixed words are not.
+			if c.Case == CaseLower {
+				sentences[i] = capitalize(sentence())
+			} else {
+				sentences[i] = c.Case.apply(sentence(), c.Locale)
+			}
+		}
+		return strings.Join(sentences, " ")
+	}
+	generator, bits := c.appendFromCharset(generator, float64(nsentences)*sentenceBits)
+	return generator, bits, nil
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []templatePart
+	}{
+		{"{adj} {noun}", []templatePart{{Placeholder: "adjectives"}, {Literal: " "}, {Placeholder: "nouns"}}},
+		{"x{number}y", []templatePart{{Literal: "x"}, {Placeholder: "number"}, {Literal: "y"}}},
+		{"{{{digit}}}", []templatePart{{Literal: "{"}, {Placeholder: "digit"}, {Literal: "}"}}},
+		{"{bip39}{symbol}", []templatePart{{Placeholder: "bip39"}, {Placeholder: "symbol"}}},
+		{"{determiner} {adverb}", []templatePart{{Placeholder: "determiner"}, {Literal: " "}, {Placeholder: "adverb"}}},
+		{"plain", []templatePart{{Literal: "plain"}}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTemplate(tt.input)
+		if err != nil {
+			t.Errorf("parseTemplate(%q): unexpected error: %v", tt.input, err)
+		} else if !slices.Equal(got, tt.want) {
+			t.Errorf("parseTemplate(%q): expected %v, but got %v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"", "{adj", "{}", "{nown}", "a}b", "{./words.txt}"} {
+		if _, err := parseTemplate(input); err == nil {
+			t.Errorf("parseTemplate(%q): expected an error", input)
+		}
+	}
+}
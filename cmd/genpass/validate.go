@@ -0,0 +1,152 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cions/go-options"
+)
+
+var variantSelectors = map[string]Variant{
+	"-w":                         Passphrase,
+	"--wordlist":                 Passphrase,
+	"--pattern":                  Passphrase,
+	"--passphrase-template":      Passphrase,
+	"--passphrase-from-sentence": Passphrase,
+	"--slip39-share":             Passphrase,
+	"-p":                         Password,
+	"--password":                 Password,
+	"-P":                         Password,
+	"--password-with":            Password,
+	"--emoji":                    Password,
+	"--alphabet-file":            Password,
+	"--charset-spec":             Password,
+	"-x":                         Hexadecimal,
+	"--hex":                      Hexadecimal,
+	"-u":                         Base64,
+	"--base64":                   Base64,
+}
+
+var variantOnlyOptions = map[string][]Variant{
+	"-s":                                  {Passphrase},
+	"--separator":                         {Passphrase},
+	"--passphrase-digits":                 {Passphrase},
+	"--passphrase-min-word-len-in-output": {Passphrase},
+	"--passphrase-length-chars":           {Passphrase},
+	"--append-from":                       {Passphrase},
+	"--append-count":                      {Passphrase},
+	"--case":                              {Passphrase},
+	"--randomize-case":                    {Passphrase},
+	"--locale":                            {Passphrase},
+	"--words-only":                        {Passphrase},
+	"--passphrase-acrostic":               {Passphrase},
+	"--wordlist-cache":                    {Passphrase},
+	"--no-cache":                          {Passphrase},
+	"--refresh":                           {Passphrase},
+	"--wordlist-format":                   {Passphrase},
+	"--wordlist-column":                   {Passphrase},
+	"--wordlist-sep":                      {Passphrase},
+	"--allow-empty-wordlist-lines":        {Passphrase},
+	"--trim-wordlist-whitespace":          {Passphrase},
+	"--no-ambiguous-words":                {Passphrase},
+	"--ambiguous-words-file":              {Passphrase},
+	"--first-char-class":                  {Password},
+	"--last-char-class":                   {Password},
+	"--insert":                            {Password},
+	"--printable-only":                    {Password},
+	"--exclude-homoglyphs":                {Password},
+	"--allowed-chars-report":              {Password},
+	"--test-seed":                         {Passphrase},
+	"--min-classes":                       {Password},
+	"--min-unique-chars":                  {Password},
+	"--min-digits":                        {Password},
+	"--min-symbols":                       {Password},
+	"--reject-sequential":                 {Password},
+	"--shuffle":                           {Password},
+	"--weighted":                          {Password},
+	"--assert-no-dupes-in-charset":        {Password},
+	"--pepper":                            {Hexadecimal, Base64},
+	"--split":                             {Hexadecimal, Base64},
+}
+
+var exclusiveOptions = map[string][]string{
+	"--passphrase-template":      {"--pattern", "--slip39-share", "--passphrase-from-sentence", "-l", "--length", "--passphrase-digits", "--passphrase-length-chars", "--passphrase-acrostic", "--max-bytes"},
+	"--passphrase-from-sentence": {"--pattern", "--slip39-share", "--passphrase-digits", "--passphrase-length-chars", "--passphrase-acrostic", "--max-bytes"},
+	"--words-only":               {"-s", "--separator"},
+	"--split":                    {"--hyphenate-every", "--wrap", "--also", "--hash", "--ensure-printable-ascii", "--unique", "--count-from", "--max-bytes", "-e", "--show-bits", "--estimate"},
+}
+
+func (c *Command) validate() error {
+	var conflicts []string
+
+	var selector string
+	for _, name := range c.given {
+		variant, ok := variantSelectors[name]
+		if !ok {
+			continue
+		}
+		if selector != "" && variantSelectors[selector] != variant {
+			conflicts = append(conflicts, fmt.Sprintf("%v and %v select different variants", selector, name))
+		}
+		selector = name
+	}
+
+	var reported []string
+	for _, name := range c.given {
+		allowed, ok := variantOnlyOptions[name]
+		if !ok || slices.Contains(allowed, c.Variant) || slices.Contains(reported, name) {
+			continue
+		}
+		names := make([]string, len(allowed))
+		for i, variant := range allowed {
+			names[i] = variants[variant].Name
+		}
+		conflicts = append(conflicts, fmt.Sprintf("%v can be used only with %v, not %v", name, strings.Join(names, " or "), variants[c.Variant].Name))
+		reported = append(reported, name)
+	}
+
+	var excluding []string
+	for _, name := range c.given {
+		excluded, ok := exclusiveOptions[name]
+		if !ok || slices.Contains(excluding, name) {
+			continue
+		}
+		excluding = append(excluding, name)
+		var others []string
+		for _, other := range c.given {
+			if slices.Contains(excluded, other) && !slices.Contains(others, other) {
+				others = append(others, other)
+			}
+		}
+		if len(others) != 0 {
+			conflicts = append(conflicts, fmt.Sprintf("%v cannot be used with %v", name, strings.Join(others, ", ")))
+		}
+	}
+
+	var stdinReaders []string
+	if c.Variant == Passphrase && !c.SLIP39Share {
+		if slices.Contains(c.Pattern, "-") {
+			stdinReaders = append(stdinReaders, "--pattern with -")
+		} else if len(c.Pattern) == 0 && c.Wordlist == "-" {
+			stdinReaders = append(stdinReaders, "--wordlist=-")
+		}
+	}
+	if c.MnemonicChecksum {
+		stdinReaders = append(stdinReaders, "--mnemonic-checksum")
+	}
+	if c.Interactive {
+		stdinReaders = append(stdinReaders, "--interactive")
+	}
+	if len(stdinReaders) > 1 {
+		conflicts = append(conflicts, fmt.Sprintf("%v all read from the standard input", strings.Join(stdinReaders, ", ")))
+	}
+
+	if len(conflicts) != 0 {
+		return options.Errorf("conflicting options:\n  %v", strings.Join(conflicts, "\n  "))
+	}
+	return nil
+}
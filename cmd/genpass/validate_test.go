@@ -0,0 +1,80 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cions/go-options"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		args      []string
+		conflicts int
+	}{
+		{[]string{}, 0},
+		{[]string{"-p", "-P", `\d`}, 0},
+		{[]string{"-w", "eff-short1", "-s", "-"}, 0},
+		{[]string{"-x", "--pepper", "foo"}, 0},
+		{[]string{"-u", "--pepper", "foo"}, 0},
+		{[]string{"-p", "--min-classes", "2", "--shuffle"}, 0},
+		{[]string{"-p", "-x"}, 1},
+		{[]string{"--passphrase-template", "{adj}", "-l", "3"}, 1},
+		{[]string{"--passphrase-template", "{adj}", "--pattern", "adj", "--max-bytes", "20"}, 1},
+		{[]string{"--passphrase-from-sentence", "-l", "3"}, 0},
+		{[]string{"--passphrase-from-sentence", "--passphrase-digits", "2"}, 1},
+		{[]string{"-x", "--split", "2", "--unique"}, 1},
+		{[]string{"-x", "--split", "2", "-e", "--count-from", "1", "--max-bytes", "8"}, 1},
+		{[]string{"-x", "-w", "eff-short1"}, 1},
+		{[]string{"--pattern", "adj,noun", "-u"}, 1},
+		{[]string{"--slip39-share", "--emoji"}, 1},
+		{[]string{"-p", "-x", "-u"}, 2},
+		{[]string{"-x", "-s", "-"}, 1},
+		{[]string{"-x", "--passphrase-digits", "2"}, 1},
+		{[]string{"-p", "--case", "upper"}, 1},
+		{[]string{"-p", "--randomize-case"}, 1},
+		{[]string{"-u", "--words-only"}, 1},
+		{[]string{"--words-only", "-s", "-"}, 1},
+		{[]string{"-s", "-", "--words-only", "--separator", "+"}, 1},
+		{[]string{"-p", "--wordlist-format", "tsv"}, 1},
+		{[]string{"-x", "--first-char-class", `\d`}, 1},
+		{[]string{"-u", "--last-char-class", `\d`}, 1},
+		{[]string{"--printable-only"}, 1},
+		{[]string{"-x", "--min-classes", "2"}, 1},
+		{[]string{"--shuffle"}, 1},
+		{[]string{"--weighted"}, 1},
+		{[]string{"--pepper", "foo"}, 1},
+		{[]string{"-p", "--pepper", "foo"}, 1},
+		{[]string{"-x", "-s", "-", "-s", "+"}, 1},
+		{[]string{"-w", "-", "--mnemonic-checksum"}, 1},
+		{[]string{"--pattern", "-,nouns", "--interactive"}, 1},
+		{[]string{"--mnemonic-checksum", "--interactive"}, 1},
+		{[]string{"-w", "-", "--mnemonic-checksum", "--interactive"}, 1},
+		{[]string{"-w", "-", "-x", "--interactive"}, 1},
+		{[]string{"-w", "-", "--slip39-share", "--interactive"}, 0},
+		{[]string{"--pattern", "-,nouns,-"}, 0},
+	}
+
+	for _, tt := range tests {
+		c := &Command{}
+		if _, err := options.Parse(c, tt.args); err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.args, err)
+		}
+		err := c.validate()
+		if tt.conflicts == 0 {
+			if err != nil {
+				t.Errorf("%q: unexpected error: %v", tt.args, err)
+			}
+			continue
+		}
+		if !errors.Is(err, options.ErrCmdline) {
+			t.Errorf("%q: expected a command-line error, but got %v", tt.args, err)
+		} else if got := strings.Count(err.Error(), "\n"); got != tt.conflicts {
+			t.Errorf("%q: expected %v conflicts, but got %v: %v", tt.args, tt.conflicts, got, err)
+		}
+	}
+}
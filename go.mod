@@ -5,9 +5,9 @@ go 1.24.0
 require (
 	github.com/cions/go-colorterm v0.3.0
 	github.com/cions/go-options v0.2.1
+	golang.org/x/crypto v0.41.0
+	golang.org/x/term v0.34.0
+	golang.org/x/text v0.28.0
 )
 
-require (
-	golang.org/x/sys v0.35.0 // indirect
-	golang.org/x/term v0.34.0 // indirect
-)
+require golang.org/x/sys v0.35.0 // indirect
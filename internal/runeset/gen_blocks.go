@@ -0,0 +1,73 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+//go:build ignore
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var lineRe = regexp.MustCompile(`^([0-9A-F]+)\.\.([0-9A-F]+); (.+)$`)
+var versionRe = regexp.MustCompile(`^# Blocks-(\d+\.\d+\.\d+)\.txt`)
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: go run gen_blocks.go Blocks.txt")
+	}
+	f, err := os.Open(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	var version string
+	var entries bytes.Buffer
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := versionRe.FindStringSubmatch(line); m != nil {
+			version = m[1]
+			continue
+		}
+		m := lineRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		fmt.Fprintf(&entries, "\t%q: {0x%v, 0x%v}, // %v\n", blockKey(m[3]), m[1], m[2], m[3])
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	if version != unicode.Version {
+		log.Fatalf("Blocks.txt is for Unicode %v, but unicode.Version is %v", version, unicode.Version)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen_blocks.go from Blocks.txt (Unicode %v). DO NOT EDIT.\n\n", version)
+	b.WriteString("package runeset\n\n")
+	b.WriteString("var blocks = map[string]Range{\n")
+	b.Write(entries.Bytes())
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("blocks.go", src, 0o666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func blockKey(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name))
+}
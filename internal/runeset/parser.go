@@ -6,12 +6,37 @@ package runeset
 import (
 	"fmt"
 	"io"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 )
 
+const (
+	surrogateMin = 0xD800
+	surrogateMax = 0xDFFF
+)
+
+func addRange(set *RuneSet, lo, hi rune) {
+	if lo < surrogateMin {
+		set.AddRange(lo, min(hi, surrogateMin-1))
+	}
+	if hi > surrogateMax {
+		set.AddRange(max(lo, surrogateMax+1), hi)
+	}
+}
+
+func addRangeTable(set *RuneSet, table *unicode.RangeTable) {
+	surrogates := RuneSet{[]Range{{surrogateMin, surrogateMax}}}
+	set.AddRangeTable(table)
+	*set = set.Subtract(&surrogates)
+}
+
+func blockKey(name string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name))
+}
+
 func decodeCharClass(set *RuneSet, s string) (int, error) {
 	if len(s) < 2 || s[0] != '\\' {
 		return 0, nil
@@ -46,7 +71,7 @@ func decodeCharClass(set *RuneSet, s string) (int, error) {
 		}
 		if s[2] != '{' {
 			if table, ok := unicode.Categories[string(s[2])]; ok {
-				set.AddRangeTable(table)
+				addRangeTable(set, table)
 			} else {
 				return 0, fmt.Errorf("invalid character class name: %s", s[:3])
 			}
@@ -58,9 +83,15 @@ func decodeCharClass(set *RuneSet, s string) (int, error) {
 		}
 		name := s[3:end]
 		if table, ok := unicode.Categories[name]; ok {
-			set.AddRangeTable(table)
+			addRangeTable(set, table)
 		} else if table, ok := unicode.Scripts[name]; ok {
-			set.AddRangeTable(table)
+			addRangeTable(set, table)
+		} else if block, ok := strings.CutPrefix(name, "Is"); ok {
+			r, ok := blocks[blockKey(block)]
+			if !ok {
+				return 0, fmt.Errorf("unknown Unicode block: %s", s[:end+1])
+			}
+			addRange(set, r.lo, r.hi)
 		} else {
 			return 0, fmt.Errorf("invalid character class name: %s", s[:end+1])
 		}
@@ -134,36 +165,109 @@ func decodeChar(s string) (rune, int, error) {
 	}
 }
 
+type ParseOptions struct {
+	Universe        *RuneSet
+	MergeAdjacents  bool
+	CaseInsensitive bool
+}
+
 func Parse(s string) (RuneSet, error) {
+	return ParseWithOptions(s, ParseOptions{MergeAdjacents: true})
+}
+
+func ParseWithOptions(s string, opts ParseOptions) (RuneSet, error) {
+	var complement bool
+	if opts.Universe != nil {
+		s, complement = strings.CutPrefix(s, "^")
+	}
+	set, err := ParseRaw(s)
+	if err != nil {
+		return RuneSet{}, err
+	}
+	if opts.CaseInsensitive {
+		set = foldCase(&set)
+	}
+	if complement {
+		set = opts.Universe.Subtract(&set)
+	}
+	if opts.MergeAdjacents {
+		set.MergeAdjacents()
+	}
+	return set, nil
+}
+
+func ParseRaw(s string) (RuneSet, error) {
 	var set RuneSet
+	err := parseTerms(s, func(term *RuneSet) {
+		for _, r := range term.ranges {
+			set.AddRange(r.lo, r.hi)
+		}
+	})
+	if err != nil {
+		return RuneSet{}, err
+	}
+	return set, nil
+}
 
+func ParseDuplicates(s string) (RuneSet, error) {
+	var seen, dupes RuneSet
+	err := parseTerms(s, func(term *RuneSet) {
+		for _, r := range seen.Intersect(term).ranges {
+			dupes.AddRange(r.lo, r.hi)
+		}
+		for _, r := range term.ranges {
+			seen.AddRange(r.lo, r.hi)
+		}
+	})
+	if err != nil {
+		return RuneSet{}, err
+	}
+	dupes.MergeAdjacents()
+	return dupes, nil
+}
+
+func foldCase(set *RuneSet) RuneSet {
+	ranges := slices.Clone(set.ranges)
+	for _, r := range set.ranges {
+		for c := r.lo; c <= r.hi; c++ {
+			for f := unicode.SimpleFold(c); f != c; f = unicode.SimpleFold(f) {
+				ranges = append(ranges, Range{f, f})
+			}
+		}
+	}
+	return RuneSet{ranges: mergeOverlaps(ranges)}
+}
+
+func parseTerms(s string, yield func(term *RuneSet)) error {
 	for len(s) != 0 {
-		if size, err := decodeCharClass(&set, s); err != nil {
-			return RuneSet{}, err
+		var term RuneSet
+		if size, err := decodeCharClass(&term, s); err != nil {
+			return err
 		} else if size != 0 {
+			yield(&term)
 			s = s[size:]
 			continue
 		}
 
 		lo, losize, err := decodeChar(s)
 		if err != nil {
-			return RuneSet{}, err
+			return err
 		}
 		if len(s) > losize && s[losize] == '-' {
 			hi, hisize, err := decodeChar(s[losize+1:])
 			if err == nil {
 				if lo > hi {
-					return RuneSet{}, fmt.Errorf("bad character range: %s", s[:losize+hisize+1])
+					return fmt.Errorf("bad character range: %s", s[:losize+hisize+1])
 				}
-				set.AddRange(lo, hi)
+				addRange(&term, lo, hi)
+				yield(&term)
 				s = s[losize+hisize+1:]
 				continue
 			}
 		}
-		set.Add(lo)
+		term.Add(lo)
+		yield(&term)
 		s = s[losize:]
 	}
-
-	set.MergeAdjacents()
-	return set, nil
+	return nil
 }
@@ -4,8 +4,11 @@
 package runeset_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/cions/genpass/internal/runeset"
 )
@@ -14,7 +17,7 @@ func uniCharClass(table *unicode.RangeTable) string {
 	var set runeset.RuneSet
 	set.AddRangeTable(table)
 	set.MergeAdjacents()
-	return set.String()
+	return set.StringEscaped()
 }
 
 func TestParse(t *testing.T) {
@@ -26,26 +29,37 @@ func TestParse(t *testing.T) {
 		{`a`, "a-a"},
 		{`\-`, "---"},
 		{`\\`, "\\-\\"},
-		{`\0`, "\u0000-\u0000"},
-		{`\a`, "\u0007-\u0007"},
-		{`\b`, "\u0008-\u0008"},
-		{`\t`, "\u0009-\u0009"},
-		{`\n`, "\u000A-\u000A"},
-		{`\v`, "\u000B-\u000B"},
-		{`\f`, "\u000C-\u000C"},
-		{`\r`, "\u000D-\u000D"},
-		{`\e`, "\u001B-\u001B"},
-		{`\xFF`, "\u00FF-\u00FF"},
-		{`\u3042`, "あ-あ"},
-		{`\U0001F200`, "🈀-🈀"},
+		{`\0`, `\u0000-\u0000`},
+		{`\a`, `\u0007-\u0007`},
+		{`\b`, `\u0008-\u0008`},
+		{`\t`, `\u0009-\u0009`},
+		{`\n`, `\u000A-\u000A`},
+		{`\v`, `\u000B-\u000B`},
+		{`\f`, `\u000C-\u000C`},
+		{`\r`, `\u000D-\u000D`},
+		{`\e`, `\u001B-\u001B`},
+		{`\xFF`, `\u00FF-\u00FF`},
+		{`\u3042`, `\u3042-\u3042`},
+		{`\U0001F200`, `\U0001F200-\U0001F200`},
 		{`ABCabc012`, "0-2A-Ca-c"},
 		{`A-Ca-c0-2`, "0-2A-Ca-c"},
 		{`a-zA-Z0-A`, "0-Za-z"},
-		{`ぁ-ゖ`, "ぁ-ゖ"},
-		{`ぁ-\u3096`, "ぁ-ゖ"},
-		{`\u3041-ゖ`, "ぁ-ゖ"},
-		{`\u3041-\u3096`, "ぁ-ゖ"},
-		{`\U00020000-\U0002A6DF`, "\U00020000-\U0002A6DF"},
+		{`ぁ-ゖ`, `\u3041-\u3096`},
+		{`ぁ-\u3096`, `\u3041-\u3096`},
+		{`\u3041-ゖ`, `\u3041-\u3096`},
+		{`\u3041-\u3096`, `\u3041-\u3096`},
+		{`\U00020000-\U0002A6DF`, `\U00020000-\U0002A6DF`},
+		{`\t-\r`, `\u0009-\u000D`},
+		{`\uD7FF-\uE000`, `\uD7FF-\uD7FF\uE000-\uE000`},
+		{`\p{Cs}`, ""},
+		{`\x00-\U0010FFFF`, `\u0000-\uD7FF\uE000-\U0010FFFF`},
+		{`\0-\e`, `\u0000-\u001B`},
+		{`\x00-\x1F`, `\u0000-\u001F`},
+		{`\t-A`, `\u0009-A`},
+		{`!-\x7E`, "!-~"},
+		{`\--/`, "--/"},
+		{`+-\-`, "+--"},
+		{`\\-a`, "\\-a"},
 		{`\d`, "0-9"},
 		{`\l`, "a-z"},
 		{`\L`, "A-Z"},
@@ -55,6 +69,13 @@ func TestParse(t *testing.T) {
 		{`\pL`, uniCharClass(unicode.L)},
 		{`\p{Hiragana}`, uniCharClass(unicode.Hiragana)},
 		{`\w\s\g\p{Lo}`, "!-~" + uniCharClass(unicode.Lo)},
+		{`\p{IsBasicLatin}`, `\u0000-\u007F`},
+		{`\p{IsCyrillic}`, `\u0400-\u04FF`},
+		{`\p{IsLatin-1 Supplement}`, `\u0080-\u00FF`},
+		{`\p{IsGreek_And_Coptic}`, `\u0370-\u03FF`},
+		{`\p{IsHighSurrogates}`, ""},
+		{`\p{IsKawi}`, `\U00011F00-\U00011F5F`},
+		{`\p{IsEgyptianHieroglyphFormatControls}`, `\U00013430-\U0001345F`},
 		{`-a`, "---a-a"},
 		{`a-`, "---a-a"},
 		{`a\-z`, "---a-az-z"},
@@ -67,12 +88,109 @@ func TestParse(t *testing.T) {
 		s, err := runeset.Parse(tt.input)
 		if err != nil {
 			t.Errorf("Parse(%q): unexpected error: %v", tt.input, err)
-		} else if got := s.String(); got != tt.want {
+		} else if got := s.StringEscaped(); got != tt.want {
 			t.Errorf("Parse(%q): expected %v, but got %v", tt.input, tt.want, got)
 		}
 	}
 }
 
+func TestParseRaw(t *testing.T) {
+	tests := []struct {
+		input  string
+		raw    string
+		merged string
+	}{
+		{`a-cd-f`, "a-cd-f", "a-f"},
+		{`a-cb-de-f`, "a-de-f", "a-f"},
+		{`xa-cyb`, "a-cx-xy-y", "a-cx-y"},
+		{`zyx`, "x-xy-yz-z", "x-z"},
+	}
+
+	for _, tt := range tests {
+		raw, err := runeset.ParseRaw(tt.input)
+		if err != nil {
+			t.Errorf("ParseRaw(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got := raw.String(); got != tt.raw {
+			t.Errorf("ParseRaw(%q): expected %v, but got %v", tt.input, tt.raw, got)
+		}
+		merged, err := runeset.Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got := merged.String(); got != tt.merged {
+			t.Errorf("Parse(%q): expected %v, but got %v", tt.input, tt.merged, got)
+		}
+	}
+}
+
+func TestParseWithOptions(t *testing.T) {
+	digits, err := runeset.Parse(`\d`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		opts  runeset.ParseOptions
+		want  string
+	}{
+		{`a-cd`, runeset.ParseOptions{}, "a-cd-d"},
+		{`a-cd`, runeset.ParseOptions{MergeAdjacents: true}, "a-d"},
+		{`^a`, runeset.ParseOptions{}, "^-^a-a"},
+		{`^1-3`, runeset.ParseOptions{Universe: &digits}, "0-04-9"},
+		{`^1-34`, runeset.ParseOptions{Universe: &digits, MergeAdjacents: true}, "0-05-9"},
+		{`^\d`, runeset.ParseOptions{Universe: &digits}, ""},
+		{`1^`, runeset.ParseOptions{Universe: &digits}, "1-1^-^"},
+		{`a-c`, runeset.ParseOptions{CaseInsensitive: true}, "A-AB-BC-Ca-c"},
+		{`a-c`, runeset.ParseOptions{CaseInsensitive: true, MergeAdjacents: true}, "A-Ca-c"},
+		{`k`, runeset.ParseOptions{CaseInsensitive: true}, `K-Kk-k\u212A-\u212A`},
+		{`σ`, runeset.ParseOptions{CaseInsensitive: true, MergeAdjacents: true}, `\u03A3-\u03A3\u03C2-\u03C3`},
+		{`^a`, runeset.ParseOptions{Universe: &digits, CaseInsensitive: true}, "0-9"},
+	}
+	for _, tt := range tests {
+		set, err := runeset.ParseWithOptions(tt.input, tt.opts)
+		if err != nil {
+			t.Errorf("ParseWithOptions(%q, %+v): unexpected error: %v", tt.input, tt.opts, err)
+			continue
+		}
+		assertEqual(t, set, tt.want, "ParseWithOptions(%q, %+v)", tt.input, tt.opts)
+	}
+}
+
+func TestParseDuplicates(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{``, ""},
+		{`abc`, ""},
+		{`a-zaeiou`, "a-ae-ei-io-ou-u"},
+		{`aa`, "a-a"},
+		{`a-fd-k`, "d-f"},
+		{`\d\w`, "0-9"},
+		{`\l\L`, ""},
+		{`\p{Greek}α`, `\u03B1-\u03B1`},
+	}
+
+	for _, tt := range tests {
+		got, err := runeset.ParseDuplicates(tt.input)
+		if err != nil {
+			t.Errorf("ParseDuplicates(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got.StringEscaped() != tt.want {
+			t.Errorf("ParseDuplicates(%q): expected %v, but got %v", tt.input, tt.want, got.StringEscaped())
+		}
+	}
+
+	if _, err := runeset.ParseDuplicates(`\q`); err == nil {
+		t.Errorf("ParseDuplicates(%q): expected an error", `\q`)
+	}
+}
+
 func TestParse_errors(t *testing.T) {
 	tests := []string{
 		`\`,
@@ -92,7 +210,12 @@ func TestParse_errors(t *testing.T) {
 		`\p{}`,
 		`\p{Greek`,
 		`\p{INVALID}`,
+		`\p{IsINVALID}`,
+		`\p{Is}`,
 		`z-a`,
+		`\r-\t`,
+		`\x7F-\x00`,
+		`\t-\q`,
 	}
 
 	for _, tt := range tests {
@@ -101,3 +224,52 @@ func TestParse_errors(t *testing.T) {
 		}
 	}
 }
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		``, `a`, `a-z`, `\w\s`, `\-`, `\\-a`, `--/`, `\x00-\x1F`, `퟿-`,
+		`\U0010FFFF`, `\p{Greek}`, `\p{IsCyrillic}`, `\pL`, `a-cb-dx`, "\xFF", `\`,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		set, err := runeset.Parse(s)
+		if err != nil {
+			return
+		}
+
+		ranges := set.Ranges()
+		var b strings.Builder
+		for i, r := range ranges {
+			if r.Lo() > r.Hi() {
+				t.Fatalf("Parse(%q): range %q-%q is reversed", s, r.Lo(), r.Hi())
+			}
+			if !utf8.ValidRune(r.Lo()) || !utf8.ValidRune(r.Hi()) || (r.Lo() <= 0xDFFF && r.Hi() >= 0xD800) {
+				t.Fatalf("Parse(%q): range %U-%U contains invalid runes", s, r.Lo(), r.Hi())
+			}
+			if i > 0 && ranges[i-1].Hi()+1 >= r.Lo() {
+				t.Fatalf("Parse(%q): ranges are not sorted or not merged: %v", s, set.String())
+			}
+			fmt.Fprintf(&b, `\U%08X-\U%08X`, r.Lo(), r.Hi())
+		}
+
+		again, err := runeset.Parse(b.String())
+		if err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", b.String(), err)
+		}
+		if again.String() != set.String() {
+			t.Fatalf("Parse(%q): expected %q, but got %q", b.String(), set.String(), again.String())
+		}
+
+		picker := set.Picker()
+		for _, i := range []int64{0, picker.Size() / 2, picker.Size() - 1} {
+			if i < 0 || i >= picker.Size() {
+				continue
+			}
+			if r := picker.Get(i); !utf8.ValidRune(r) {
+				t.Fatalf("Parse(%q): Get(%v) returned an invalid rune %U", s, i, r)
+			}
+		}
+	})
+}
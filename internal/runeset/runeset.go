@@ -4,12 +4,18 @@
 package runeset
 
 import (
+	"cmp"
 	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math"
 	"math/big"
 	"slices"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 type Range struct {
@@ -26,6 +32,14 @@ type Picker struct {
 	size     int64
 }
 
+func (r Range) Lo() rune {
+	return r.lo
+}
+
+func (r Range) Hi() rune {
+	return r.hi
+}
+
 func compare(a Range, b rune) int {
 	if a.hi < b {
 		return -1
@@ -36,6 +50,14 @@ func compare(a Range, b rune) int {
 	return 0
 }
 
+func (set *RuneSet) Len() int {
+	return len(set.ranges)
+}
+
+func (set *RuneSet) Ranges() []Range {
+	return slices.Clone(set.ranges)
+}
+
 func (set *RuneSet) Add(r rune) {
 	i, found := slices.BinarySearchFunc(set.ranges, r, compare)
 	if !found {
@@ -59,25 +81,56 @@ func (set *RuneSet) AddRange(lo, hi rune) {
 	set.ranges = slices.Replace(set.ranges, i, j, Range{lo, hi})
 }
 
-func (set *RuneSet) AddRangeTable(table *unicode.RangeTable) {
+func appendStrided(ranges []Range, lo, hi, stride uint32) []Range {
+	if stride == 0 {
+		panic("runeset: stride must not be zero")
+	}
+	if stride == 1 {
+		return append(ranges, Range{rune(lo), rune(hi)})
+	}
+	for x := lo; x <= hi; x += stride {
+		ranges = append(ranges, Range{rune(x), rune(x)})
+	}
+	return ranges
+}
+
+func appendRangeTable(ranges []Range, table *unicode.RangeTable) []Range {
 	for _, r := range table.R16 {
-		if r.Stride == 1 {
-			set.AddRange(rune(r.Lo), rune(r.Hi))
-		} else {
-			for x := r.Lo; x <= r.Hi; x += r.Stride {
-				set.Add(rune(x))
-			}
-		}
+		ranges = appendStrided(ranges, uint32(r.Lo), uint32(r.Hi), uint32(r.Stride))
 	}
 	for _, r := range table.R32 {
-		if r.Stride == 1 {
-			set.AddRange(rune(r.Lo), rune(r.Hi))
-		} else {
-			for x := r.Lo; x <= r.Hi; x += r.Stride {
-				set.Add(rune(x))
-			}
+		ranges = appendStrided(ranges, r.Lo, r.Hi, r.Stride)
+	}
+	return ranges
+}
+
+func mergeOverlaps(ranges []Range) []Range {
+	slices.SortFunc(ranges, func(a, b Range) int {
+		return cmp.Compare(a.lo, b.lo)
+	})
+
+	i := 0
+	for _, r := range ranges {
+		if i > 0 && r.lo <= ranges[i-1].hi {
+			ranges[i-1].hi = max(ranges[i-1].hi, r.hi)
+			continue
 		}
+		ranges[i] = r
+		i++
 	}
+	return ranges[:i]
+}
+
+func FromRangeTables(tables ...*unicode.RangeTable) RuneSet {
+	var ranges []Range
+	for _, table := range tables {
+		ranges = appendRangeTable(ranges, table)
+	}
+	return RuneSet{ranges: mergeOverlaps(ranges)}
+}
+
+func (set *RuneSet) AddRangeTable(table *unicode.RangeTable) {
+	set.ranges = mergeOverlaps(appendRangeTable(slices.Clone(set.ranges), table))
 }
 
 func (set *RuneSet) MergeAdjacents() {
@@ -95,6 +148,81 @@ func (set *RuneSet) MergeAdjacents() {
 	set.ranges = set.ranges[:i]
 }
 
+// Normalize sorts the ranges and merges overlapping and adjacent ones.
+// Picker and String assume a normalized set.
+func (set *RuneSet) Normalize() {
+	set.ranges = mergeOverlaps(set.ranges)
+	set.MergeAdjacents()
+}
+
+func (set *RuneSet) Union(other *RuneSet) RuneSet {
+	ranges := append(slices.Clone(set.ranges), other.ranges...)
+	return RuneSet{ranges: mergeOverlaps(ranges)}
+}
+
+func (set *RuneSet) Intersect(other *RuneSet) RuneSet {
+	var result RuneSet
+	i, j := 0, 0
+	for i < len(set.ranges) && j < len(other.ranges) {
+		a, b := set.ranges[i], other.ranges[j]
+		if lo, hi := max(a.lo, b.lo), min(a.hi, b.hi); lo <= hi {
+			result.ranges = append(result.ranges, Range{lo, hi})
+		}
+		if a.hi < b.hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
+
+func (set *RuneSet) Subtract(other *RuneSet) RuneSet {
+	var result RuneSet
+	j := 0
+	for _, a := range set.ranges {
+		lo := a.lo
+		for j < len(other.ranges) && other.ranges[j].hi < lo {
+			j++
+		}
+		for k := j; k < len(other.ranges) && other.ranges[k].lo <= a.hi; k++ {
+			b := other.ranges[k]
+			if b.lo > lo {
+				result.ranges = append(result.ranges, Range{lo, b.lo - 1})
+			}
+			lo = b.hi + 1
+		}
+		if lo <= a.hi {
+			result.ranges = append(result.ranges, Range{lo, a.hi})
+		}
+	}
+	return result
+}
+
+var classSets = []struct {
+	name string
+	set  RuneSet
+}{
+	{"digit", FromRangeTables(unicode.Nd)},
+	{"lower", FromRangeTables(unicode.Ll)},
+	{"upper", FromRangeTables(unicode.Lu)},
+	{"punct", FromRangeTables(unicode.P, unicode.S)},
+}
+
+// SplitByClass partitions the set into decimal digits (Nd), lowercase (Ll)
+// and uppercase (Lu) letters, punctuation and symbols (P and S), and the
+// remaining runes, keyed by "digit", "lower", "upper", "punct" and "other".
+func (set *RuneSet) SplitByClass() map[string]RuneSet {
+	classes := make(map[string]RuneSet, len(classSets)+1)
+	rest := *set
+	for _, class := range classSets {
+		classes[class.name] = set.Intersect(&class.set)
+		rest = rest.Subtract(&class.set)
+	}
+	classes["other"] = rest
+	return classes
+}
+
 func (set *RuneSet) Picker() *Picker {
 	var size int64
 	cumsizes := make([]int64, len(set.ranges))
@@ -115,13 +243,107 @@ func (set *RuneSet) String() string {
 	return b.String()
 }
 
+func (set *RuneSet) StringEscaped() string {
+	var b []byte
+	for _, r := range set.ranges {
+		b = appendASCII(b, r.lo)
+		b = append(b, '-')
+		b = appendASCII(b, r.hi)
+	}
+	return string(b)
+}
+
+func appendASCII(b []byte, r rune) []byte {
+	switch {
+	case r >= 0x20 && r <= 0x7E:
+		return append(b, byte(r))
+	case r <= 0xFFFF:
+		return fmt.Appendf(b, `\u%04X`, r)
+	default:
+		return fmt.Appendf(b, `\U%08X`, r)
+	}
+}
+
+func appendEscaped(b []byte, r rune, first bool) []byte {
+	switch {
+	case r == '\\' || r == '-':
+		return append(b, '\\', byte(r))
+	case r == '^' && first:
+		return append(b, `\x5E`...)
+	case unicode.IsGraphic(r) && r != ' ':
+		return utf8.AppendRune(b, r)
+	case r <= 0xFF:
+		return fmt.Appendf(b, `\x%02X`, r)
+	case r <= 0xFFFF:
+		return fmt.Appendf(b, `\u%04X`, r)
+	default:
+		return fmt.Appendf(b, `\U%08X`, r)
+	}
+}
+
+func (set RuneSet) MarshalText() ([]byte, error) {
+	var b []byte
+	for _, r := range set.ranges {
+		b = appendEscaped(b, r.lo, len(b) == 0)
+		switch {
+		case r.hi == r.lo+1:
+			b = appendEscaped(b, r.hi, false)
+		case r.hi > r.lo:
+			b = append(b, '-')
+			b = appendEscaped(b, r.hi, false)
+		}
+	}
+	return b, nil
+}
+
+func (set *RuneSet) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*set = parsed
+	return nil
+}
+
+func (set RuneSet) MarshalJSON() ([]byte, error) {
+	text, err := set.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (set *RuneSet) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return set.UnmarshalText([]byte(s))
+}
+
 func (p *Picker) Size() int64 {
 	return p.size
 }
 
+func (p *Picker) Bits() float64 {
+	return math.Log2(float64(p.size))
+}
+
+func (p *Picker) Runes() []rune {
+	return p.GetSlice(0, p.size)
+}
+
 func (p *Picker) Get(i int64) rune {
+	r, ok := p.TryGet(i)
+	if !ok {
+		panic(fmt.Sprintf("runeset: index %d out of range [0:%d]", i, p.size))
+	}
+	return r
+}
+
+func (p *Picker) TryGet(i int64) (rune, bool) {
 	if i < 0 || i >= p.size {
-		panic("runeset: out of bounds")
+		return 0, false
 	}
 	ridx, found := slices.BinarySearch(p.cumSizes, i)
 	if found {
@@ -131,7 +353,34 @@ func (p *Picker) Get(i int64) rune {
 	if ridx > 0 {
 		offset -= p.cumSizes[ridx-1]
 	}
-	return p.ranges[ridx].lo + rune(offset)
+	return p.ranges[ridx].lo + rune(offset), true
+}
+
+func (p *Picker) GetSlice(start, end int64) []rune {
+	if start < 0 || end > p.size || start > end {
+		panic(fmt.Sprintf("runeset: slice bounds [%d:%d] out of range [0:%d]", start, end, p.size))
+	}
+	runes := make([]rune, 0, end-start)
+	if start == end {
+		return runes
+	}
+	ridx, found := slices.BinarySearch(p.cumSizes, start)
+	if found {
+		ridx++
+	}
+	offset := start
+	if ridx > 0 {
+		offset -= p.cumSizes[ridx-1]
+	}
+	for i := start; i < end; ridx++ {
+		r := p.ranges[ridx]
+		for x := r.lo + rune(offset); x <= r.hi && i < end; x++ {
+			runes = append(runes, x)
+			i++
+		}
+		offset = 0
+	}
+	return runes
 }
 
 func (p *Picker) Random() rune {
@@ -144,3 +393,64 @@ func (p *Picker) Random() rune {
 	}
 	return p.Get(i.Int64())
 }
+
+func (p *Picker) RandomString(n int) string {
+	return p.RandomStringFrom(rand.Reader, n)
+}
+
+func (p *Picker) RandomStringFrom(r io.Reader, n int) string {
+	if p.size <= 0 {
+		panic("runeset: empty picker")
+	}
+	size := uint64(p.size)
+	rem := (math.MaxUint64%size + 1) % size
+
+	var b strings.Builder
+	b.Grow(n)
+	buf := make([]byte, 8*n)
+	for count := 0; count < n; {
+		chunk := buf[:8*(n-count)]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			panic(fmt.Sprintf("crypto/rand: %v", err))
+		}
+		for len(chunk) != 0 {
+			x := binary.LittleEndian.Uint64(chunk)
+			chunk = chunk[8:]
+			if x > math.MaxUint64-rem {
+				continue
+			}
+			b.WriteRune(p.Get(int64(x % size)))
+			count++
+		}
+	}
+	return b.String()
+}
+
+func (p *Picker) Contains(r rune) bool {
+	_, found := slices.BinarySearchFunc(p.ranges, r, compare)
+	return found
+}
+
+// RandomExcept is like Random but never returns any of excluded. It draws by
+// rejection sampling, so excluded should be small compared to the picker.
+func (p *Picker) RandomExcept(excluded ...rune) rune {
+	return p.RandomExceptFrom(rand.Reader, excluded...)
+}
+
+func (p *Picker) RandomExceptFrom(r io.Reader, excluded ...rune) rune {
+	var nexcluded int64
+	for i, x := range excluded {
+		if p.Contains(x) && !slices.Contains(excluded[:i], x) {
+			nexcluded++
+		}
+	}
+	if nexcluded >= p.size {
+		panic("runeset: all runes are excluded")
+	}
+	for {
+		x, _ := utf8.DecodeRuneInString(p.RandomStringFrom(r, 1))
+		if !slices.Contains(excluded, x) {
+			return x
+		}
+	}
+}
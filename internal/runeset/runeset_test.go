@@ -4,10 +4,14 @@
 package runeset_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
+	"slices"
 	"strings"
 	"testing"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/cions/genpass/internal/runeset"
 )
@@ -15,7 +19,7 @@ import (
 func assertEqual(t *testing.T, set runeset.RuneSet, want string, a ...any) {
 	t.Helper()
 
-	if got := set.String(); got != want {
+	if got := set.StringEscaped(); got != want {
 		var prefix string
 		if len(a) != 0 {
 			prefix = fmt.Sprintf(a[0].(string), a[1:]...) + ": "
@@ -125,7 +129,26 @@ func TestRuneSet_AddRangeTable(t *testing.T) {
 
 	var set runeset.RuneSet
 	set.AddRangeTable(table)
-	assertEqual(t, set, "A-Za-ad-dg-gj-j\U00010000-\U00010010\U00010100-\U00010100\U00010110-\U00010110")
+	assertEqual(t, set, `A-Za-ad-dg-gj-j\U00010000-\U00010010\U00010100-\U00010100\U00010110-\U00010110`)
+}
+
+func TestFromRangeTables(t *testing.T) {
+	set := runeset.FromRangeTables(unicode.Latin, unicode.Greek)
+	contains := func(r rune) bool {
+		return slices.ContainsFunc(set.Ranges(), func(rng runeset.Range) bool {
+			return rng.Lo() <= r && r <= rng.Hi()
+		})
+	}
+	for r := rune(0); r <= 0x1FFFF; r++ {
+		if want := unicode.In(r, unicode.Latin, unicode.Greek); contains(r) != want {
+			t.Errorf("FromRangeTables(Latin, Greek): contains %q: expected %v, but got %v", r, want, !want)
+		}
+	}
+
+	var want runeset.RuneSet
+	want.AddRangeTable(unicode.Latin)
+	want.AddRangeTable(unicode.Greek)
+	assertEqual(t, set, want.StringEscaped())
 }
 
 func TestRuneSet_MergeAdjacents(t *testing.T) {
@@ -141,6 +164,162 @@ func TestRuneSet_MergeAdjacents(t *testing.T) {
 	assertEqual(t, set, "a-cg-ls-vx-z")
 }
 
+func TestRuneSet_Normalize(t *testing.T) {
+	var set runeset.RuneSet
+	set.AddRange('x', 'z')
+	set.Add('a')
+	set.AddRange('0', '9')
+	set.AddRange('b', 'd')
+	set.Add('c')
+	set.AddRange('e', 'w')
+	set.Add('5')
+	set.Normalize()
+	assertEqual(t, set, "0-9a-z")
+
+	set.Normalize()
+	assertEqual(t, set, "0-9a-z")
+}
+
+func TestRuneSet_Ranges(t *testing.T) {
+	var set runeset.RuneSet
+	set.AddRange('a', 'c')
+	set.AddRange('x', 'z')
+	set.Add('0')
+
+	if got := set.Len(); got != 3 {
+		t.Errorf("Len(): expected 3, but got %v", got)
+	}
+
+	ranges := set.Ranges()
+	var b strings.Builder
+	for _, r := range ranges {
+		b.WriteRune(r.Lo())
+		b.WriteRune('-')
+		b.WriteRune(r.Hi())
+	}
+	if got, want := b.String(), set.String(); got != want {
+		t.Errorf("Ranges(): expected %v, but got %v", want, got)
+	}
+
+	ranges[0] = runeset.Range{}
+	assertEqual(t, set, "0-0a-cx-z", "after modifying Ranges()")
+}
+
+func TestRuneSet_Union(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{``, ``, ""},
+		{`a-z`, ``, "a-z"},
+		{`a-m`, `k-z`, "a-z"},
+		{`a-c`, `x-z`, "a-cx-z"},
+		{`x-z`, `a-c`, "a-cx-z"},
+		{`\d`, `\L`, "0-9A-Z"},
+	}
+
+	for _, tt := range tests {
+		a, _ := runeset.Parse(tt.a)
+		b, _ := runeset.Parse(tt.b)
+		assertEqual(t, a.Union(&b), tt.want, "Parse(%q).Union(Parse(%q))", tt.a, tt.b)
+	}
+}
+
+func TestRuneSet_Intersect(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{`a-z`, `A-Z`, ""},
+		{`a-z`, `a-z`, "a-z"},
+		{`a-z`, `x-zA-C`, "x-z"},
+		{`a-mo-z`, `k-q`, "k-mo-q"},
+		{`\w`, `\L\d`, "0-9A-Z"},
+		{`a-cx-z`, `b-y`, "b-cx-y"},
+	}
+
+	for _, tt := range tests {
+		a, _ := runeset.Parse(tt.a)
+		b, _ := runeset.Parse(tt.b)
+		assertEqual(t, a.Intersect(&b), tt.want, "Parse(%q).Intersect(Parse(%q))", tt.a, tt.b)
+	}
+}
+
+func TestRuneSet_Subtract(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{`a-z`, `A-Z`, "a-z"},
+		{`a-z`, `a-z`, ""},
+		{`a-z`, `m`, "a-ln-z"},
+		{`a-z`, `a-cx-z`, "d-w"},
+		{`a-cx-z`, `b-y`, "a-az-z"},
+		{`a-ce-gx-z`, `e-g`, "a-cx-z"},
+		{`\w`, `\L\d`, "a-z"},
+		{`a-z`, `\x00-\U0010FFFF`, ""},
+		{`\x00-\U0010FFFF`, `\U0010FFFF`, `\u0000-\uD7FF\uE000-\U0010FFFE`},
+		{``, `a-z`, ""},
+	}
+
+	for _, tt := range tests {
+		a, _ := runeset.Parse(tt.a)
+		b, _ := runeset.Parse(tt.b)
+		before := a.StringEscaped()
+		assertEqual(t, a.Subtract(&b), tt.want, "Parse(%q).Subtract(Parse(%q))", tt.a, tt.b)
+		assertEqual(t, a, before, "Parse(%q) after Subtract", tt.a)
+	}
+}
+
+func TestRuneSet_SplitByClass(t *testing.T) {
+	set, err := runeset.Parse(`\g \p{Greek}０-９ぁ-ゖ`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	classes := set.SplitByClass()
+
+	var total int64
+	for _, class := range classes {
+		total += class.Picker().Size()
+	}
+	if total != set.Picker().Size() {
+		t.Errorf("SplitByClass: the classes contain %d runes in total, but the set has %d", total, set.Picker().Size())
+	}
+
+	tests := []struct {
+		class   string
+		members string
+		others  string
+	}{
+		{"digit", "09０９", "aAα!ぁ "},
+		{"lower", "azαω", "AΩ0!ぁ"},
+		{"upper", "AZΑΩ", "aω0!ぁ"},
+		{"punct", "!/:@[`{~", "a0ぁ "},
+		{"other", " ぁゖ", "a0!"},
+	}
+	for _, tt := range tests {
+		class, ok := classes[tt.class]
+		if !ok {
+			t.Errorf("SplitByClass: missing class %q", tt.class)
+			continue
+		}
+		runes := class.Picker().Runes()
+		for _, r := range tt.members {
+			if !slices.Contains(runes, r) {
+				t.Errorf("SplitByClass: %q is not in class %q", r, tt.class)
+			}
+		}
+		for _, r := range tt.others {
+			if slices.Contains(runes, r) {
+				t.Errorf("SplitByClass: %q is unexpectedly in class %q", r, tt.class)
+			}
+		}
+	}
+	if len(classes) != 5 {
+		t.Errorf("SplitByClass: expected 5 classes, but got %d", len(classes))
+	}
+}
+
 func TestRuneSet_Picker(t *testing.T) {
 	expected := "abceghijklxyz"
 
@@ -166,7 +345,229 @@ func TestRuneSet_Picker(t *testing.T) {
 		t.Errorf("expected %v, but got %v", expected, got)
 	}
 
+	if got := string(picker.Runes()); got != expected {
+		t.Errorf("Runes(): expected %v, but got %v", expected, got)
+	}
+
 	if r := picker.Random(); !strings.ContainsRune(expected, r) {
 		t.Errorf("Random() returned a non-member rune %q", r)
 	}
 }
+
+func TestPicker_Bits(t *testing.T) {
+	for _, input := range []string{`a`, `ab`, `\d`, `\g`, `\p{Han}`} {
+		set, err := runeset.Parse(input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		picker := set.Picker()
+		if got, want := picker.Bits(), math.Log2(float64(picker.Size())); got != want {
+			t.Errorf("Parse(%q).Picker().Bits(): expected %v, but got %v", input, want, got)
+		}
+	}
+}
+
+func TestPicker_GetSlice(t *testing.T) {
+	set, _ := runeset.Parse(`a-ceg-lx-z\p{Hiragana}`)
+	picker := set.Picker()
+
+	for start := range picker.Size() + 1 {
+		for end := start; end <= picker.Size(); end++ {
+			got := picker.GetSlice(start, end)
+			if int64(len(got)) != end-start {
+				t.Fatalf("GetSlice(%v, %v): expected %v runes, but got %v", start, end, end-start, len(got))
+			}
+			for i, r := range got {
+				if want := picker.Get(start + int64(i)); r != want {
+					t.Fatalf("GetSlice(%v, %v)[%v]: expected %q, but got %q", start, end, i, want, r)
+				}
+			}
+		}
+	}
+
+	for _, tt := range [][2]int64{{-1, 0}, {0, picker.Size() + 1}, {2, 1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("GetSlice(%v, %v): expected a panic", tt[0], tt[1])
+				}
+			}()
+			picker.GetSlice(tt[0], tt[1])
+		}()
+	}
+}
+
+func TestPicker_TryGet(t *testing.T) {
+	set, err := runeset.Parse(`a-cx`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	picker := set.Picker()
+
+	for i, want := range "abcx" {
+		if got, ok := picker.TryGet(int64(i)); !ok || got != want {
+			t.Errorf("TryGet(%v): expected (%q, true), but got (%q, %v)", i, want, got, ok)
+		}
+	}
+	for _, i := range []int64{-1, 4, 100} {
+		if got, ok := picker.TryGet(i); ok {
+			t.Errorf("TryGet(%v): expected (0, false), but got (%q, %v)", i, got, ok)
+		}
+	}
+
+	defer func() {
+		want := "runeset: index 4 out of range [0:4]"
+		if got := recover(); got != want {
+			t.Errorf("Get(4): expected a panic with %q, but got %v", want, got)
+		}
+	}()
+	picker.Get(4)
+}
+
+func TestPicker_RandomString(t *testing.T) {
+	set, err := runeset.Parse(`a-cぁ-ゖ\U0001F600-\U0001F64F`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	picker := set.Picker()
+
+	for _, n := range []int{0, 1, 16, 100} {
+		s := picker.RandomString(n)
+		if got := utf8.RuneCountInString(s); got != n {
+			t.Errorf("RandomString(%v): expected %v runes, but got %v", n, n, got)
+		}
+		for _, r := range s {
+			if !(r >= 'a' && r <= 'c' || r >= 'ぁ' && r <= 'ゖ' || r >= 0x1F600 && r <= 0x1F64F) {
+				t.Errorf("RandomString(%v) returned a non-member rune %q", n, r)
+			}
+		}
+	}
+}
+
+func TestPicker_RandomExcept(t *testing.T) {
+	set, err := runeset.Parse(`a-d`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	picker := set.Picker()
+
+	seen := make(map[rune]int)
+	for range 1000 {
+		seen[picker.RandomExcept('b', 'd', 'z', 'b')]++
+	}
+	if seen['b'] != 0 || seen['d'] != 0 {
+		t.Errorf("RandomExcept('b', 'd'): returned an excluded rune: %v", seen)
+	}
+	if seen['a'] == 0 || seen['c'] == 0 {
+		t.Errorf("RandomExcept('b', 'd'): never returned a non-excluded rune: %v", seen)
+	}
+	if got := picker.RandomExcept('a', 'b', 'c'); got != 'd' {
+		t.Errorf("RandomExcept('a', 'b', 'c'): expected %q, but got %q", 'd', got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("RandomExcept('a', 'b', 'c', 'd'): expected a panic")
+		}
+	}()
+	picker.RandomExcept('a', 'b', 'c', 'd')
+}
+
+func TestRuneSet_MarshalText(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{``, ``},
+		{`a-z`, `a-z`},
+		{`ab`, `ab`},
+		{`\-\\`, `\-\\`},
+		{`^a`, `\x5Ea`},
+		{`^-a`, `\x5E-a`},
+		{` \t\x7F`, `\x09\x20\x7F`},
+		{`\u00A0\u2028`, "\u00A0\\u2028"},
+		{`\U000E0001`, `\U000E0001`},
+		{`\d\L`, `0-9A-Z`},
+		{`ぁ-ゖ😀`, `ぁ-ゖ😀`},
+	}
+
+	for _, tt := range tests {
+		set, err := runeset.Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", tt.input, err)
+		}
+		text, err := set.MarshalText()
+		if err != nil {
+			t.Errorf("MarshalText(%q): unexpected error: %v", tt.input, err)
+		} else if string(text) != tt.want {
+			t.Errorf("MarshalText(%q): expected %q, but got %q", tt.input, tt.want, text)
+		}
+	}
+}
+
+func TestRuneSet_JSON(t *testing.T) {
+	inputs := []string{
+		``,
+		`\g`,
+		`\-\\"'{}`,
+		`^\d`,
+		`\0-\x1F\x7F`,
+		`\p{Greek}\p{Nd}`,
+		`\p{IsHiragana}\p{So}`,
+		`a-cx-z\U0010FFFF`,
+	}
+
+	type policy struct {
+		Charset runeset.RuneSet  `json:"charset"`
+		First   *runeset.RuneSet `json:"first"`
+	}
+	for _, input := range inputs {
+		set, err := runeset.Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): unexpected error: %v", input, err)
+		}
+		data, err := json.Marshal(policy{set, &set})
+		if err != nil {
+			t.Errorf("json.Marshal(%q): unexpected error: %v", input, err)
+			continue
+		}
+		var got policy
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Errorf("json.Unmarshal(%s): unexpected error: %v", data, err)
+			continue
+		}
+		if !slices.Equal(got.Charset.Ranges(), set.Ranges()) || !slices.Equal(got.First.Ranges(), set.Ranges()) {
+			t.Errorf("JSON round trip of %q through %s: expected %v, but got %v", input, data, set.String(), got.Charset.String())
+		}
+	}
+
+	var set runeset.RuneSet
+	if err := json.Unmarshal([]byte(`"\\q"`), &set); err == nil {
+		t.Errorf("json.Unmarshal(%q): expected an error", `"\\q"`)
+	}
+}
+
+func BenchmarkRuneSet_AddRangeTable(b *testing.B) {
+	for b.Loop() {
+		var set runeset.RuneSet
+		set.AddRangeTable(unicode.L)
+	}
+}
+
+func TestPicker_Contains(t *testing.T) {
+	set, err := runeset.Parse(`a-cx\U0001F600`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	picker := set.Picker()
+	for _, r := range "abcx\U0001F600" {
+		if !picker.Contains(r) {
+			t.Errorf("Contains(%q): expected true, but got false", r)
+		}
+	}
+	for _, r := range "dwyA\U0001F601" {
+		if picker.Contains(r) {
+			t.Errorf("Contains(%q): expected false, but got true", r)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package runeset
+
+import (
+	"cmp"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+type WeightedPicker struct {
+	ranges     []Range
+	weights    []uint64
+	cumWeights []uint64
+	size       int64
+}
+
+func ParseWeighted(s string) (*WeightedPicker, error) {
+	type event struct {
+		pos   int64
+		delta int64
+	}
+	var events []event
+	err := parseTerms(s, func(term *RuneSet) {
+		for _, r := range term.ranges {
+			events = append(events, event{int64(r.lo), 1}, event{int64(r.hi) + 1, -1})
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(events, func(a, b event) int {
+		return cmp.Compare(a.pos, b.pos)
+	})
+
+	p := &WeightedPicker{}
+	var weight, total uint64
+	for i, e := range events {
+		weight = uint64(int64(weight) + e.delta)
+		if weight == 0 || i+1 == len(events) || events[i+1].pos == e.pos {
+			continue
+		}
+		lo, hi := rune(e.pos), rune(events[i+1].pos-1)
+		total += weight * uint64(hi-lo+1)
+		p.ranges = append(p.ranges, Range{lo, hi})
+		p.weights = append(p.weights, weight)
+		p.cumWeights = append(p.cumWeights, total)
+		p.size += int64(hi-lo) + 1
+	}
+	return p, nil
+}
+
+func (p *WeightedPicker) Size() int64 {
+	return p.size
+}
+
+func (p *WeightedPicker) Weight(r rune) uint64 {
+	i, found := slices.BinarySearchFunc(p.ranges, r, compare)
+	if !found {
+		return 0
+	}
+	return p.weights[i]
+}
+
+func (p *WeightedPicker) Entropy() float64 {
+	if p.size == 0 {
+		return 0
+	}
+	total := float64(p.cumWeights[len(p.cumWeights)-1])
+	var bits float64
+	for i, r := range p.ranges {
+		prob := float64(p.weights[i]) / total
+		bits -= float64(r.hi-r.lo+1) * prob * math.Log2(prob)
+	}
+	return bits
+}
+
+func (p *WeightedPicker) get(x uint64) rune {
+	i, found := slices.BinarySearch(p.cumWeights, x)
+	if found {
+		i++
+	}
+	offset := x
+	if i > 0 {
+		offset -= p.cumWeights[i-1]
+	}
+	return p.ranges[i].lo + rune(offset/p.weights[i])
+}
+
+func (p *WeightedPicker) RandomStringFrom(r io.Reader, n int) string {
+	if p.size <= 0 {
+		panic("runeset: empty picker")
+	}
+	total := p.cumWeights[len(p.cumWeights)-1]
+	rem := (math.MaxUint64%total + 1) % total
+
+	var b strings.Builder
+	b.Grow(n)
+	buf := make([]byte, 8*n)
+	for count := 0; count < n; {
+		chunk := buf[:8*(n-count)]
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			panic(fmt.Sprintf("crypto/rand: %v", err))
+		}
+		for len(chunk) != 0 {
+			x := binary.LittleEndian.Uint64(chunk)
+			chunk = chunk[8:]
+			if x > math.MaxUint64-rem {
+				continue
+			}
+			b.WriteRune(p.get(x % total))
+			count++
+		}
+	}
+	return b.String()
+}
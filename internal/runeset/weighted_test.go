@@ -0,0 +1,82 @@
+// Copyright (c) 2024-2025 cions
+// Licensed under the MIT License. See LICENSE for details.
+
+package runeset_test
+
+import (
+	"crypto/rand"
+	"math"
+	"testing"
+
+	"github.com/cions/genpass/internal/runeset"
+)
+
+func TestParseWeighted(t *testing.T) {
+	tests := []struct {
+		input   string
+		size    int64
+		weights map[rune]uint64
+	}{
+		{`\d\l`, 36, map[rune]uint64{'0': 1, '9': 1, 'a': 1, 'z': 1, 'A': 0}},
+		{`\d\d\l`, 36, map[rune]uint64{'0': 2, '9': 2, 'a': 1, 'z': 1}},
+		{`a-ec-gd`, 7, map[rune]uint64{'a': 1, 'b': 1, 'c': 2, 'd': 3, 'e': 2, 'f': 1, 'g': 1, 'h': 0}},
+		{`\w\l`, 62, map[rune]uint64{'0': 1, 'A': 1, 'a': 2, 'z': 2}},
+		{``, 0, map[rune]uint64{'a': 0}},
+	}
+
+	for _, tt := range tests {
+		p, err := runeset.ParseWeighted(tt.input)
+		if err != nil {
+			t.Errorf("ParseWeighted(%q): unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got := p.Size(); got != tt.size {
+			t.Errorf("ParseWeighted(%q).Size(): expected %v, but got %v", tt.input, tt.size, got)
+		}
+		for r, want := range tt.weights {
+			if got := p.Weight(r); got != want {
+				t.Errorf("ParseWeighted(%q).Weight(%q): expected %v, but got %v", tt.input, r, want, got)
+			}
+		}
+	}
+
+	if _, err := runeset.ParseWeighted(`z-a`); err == nil {
+		t.Errorf("ParseWeighted(%q): expected a non-nil error", `z-a`)
+	}
+}
+
+func TestWeightedPicker_Entropy(t *testing.T) {
+	p, _ := runeset.ParseWeighted(`\d`)
+	if got, want := p.Entropy(), math.Log2(10); math.Abs(got-want) > 1e-9 {
+		t.Errorf(`\d: expected %v, but got %v`, want, got)
+	}
+
+	p, _ = runeset.ParseWeighted(`aab`)
+	want := -(2.0/3)*math.Log2(2.0/3) - (1.0/3)*math.Log2(1.0/3)
+	if got := p.Entropy(); math.Abs(got-want) > 1e-9 {
+		t.Errorf(`aab: expected %v, but got %v`, want, got)
+	}
+}
+
+func TestWeightedPicker_RandomStringFrom(t *testing.T) {
+	p, _ := runeset.ParseWeighted(`\d\d\l`)
+
+	const n = 46000
+	var digits, lowers int
+	for _, r := range p.RandomStringFrom(rand.Reader, n) {
+		switch {
+		case '0' <= r && r <= '9':
+			digits++
+		case 'a' <= r && r <= 'z':
+			lowers++
+		default:
+			t.Fatalf("unexpected rune %q", r)
+		}
+	}
+	if digits+lowers != n {
+		t.Errorf("expected %v runes, but got %v", n, digits+lowers)
+	}
+	if ratio := float64(digits) / n; math.Abs(ratio-20.0/46) > 0.02 {
+		t.Errorf("expected the ratio of digits to be about %.3f, but got %.3f", 20.0/46, ratio)
+	}
+}
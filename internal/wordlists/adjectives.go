@@ -0,0 +1,324 @@
+package wordlists
+
+var Adjectives = []string{
+	"able",
+	"absent",
+	"active",
+	"actual",
+	"agile",
+	"alert",
+	"alive",
+	"amber",
+	"ample",
+	"ancient",
+	"angry",
+	"annual",
+	"antique",
+	"anxious",
+	"arctic",
+	"ardent",
+	"artful",
+	"ashen",
+	"astute",
+	"atomic",
+	"august",
+	"autumn",
+	"awake",
+	"aware",
+	"awesome",
+	"awkward",
+	"bald",
+	"balmy",
+	"bare",
+	"basic",
+	"bitter",
+	"bland",
+	"blank",
+	"blazing",
+	"bleak",
+	"blind",
+	"blissful",
+	"blond",
+	"blue",
+	"blunt",
+	"bold",
+	"bony",
+	"bossy",
+	"brave",
+	"breezy",
+	"brief",
+	"bright",
+	"brisk",
+	"broad",
+	"bronze",
+	"brown",
+	"bubbly",
+	"bulky",
+	"bumpy",
+	"busy",
+	"calm",
+	"candid",
+	"careful",
+	"casual",
+	"cheap",
+	"cheerful",
+	"chilly",
+	"chubby",
+	"civic",
+	"classic",
+	"clean",
+	"clear",
+	"clever",
+	"cloudy",
+	"clumsy",
+	"coarse",
+	"cold",
+	"comic",
+	"cool",
+	"copper",
+	"cosmic",
+	"costly",
+	"cozy",
+	"crafty",
+	"crisp",
+	"cruel",
+	"crunchy",
+	"cubic",
+	"curly",
+	"curved",
+	"cute",
+	"daily",
+	"damp",
+	"dapper",
+	"daring",
+	"dark",
+	"dear",
+	"decent",
+	"deep",
+	"dense",
+	"dim",
+	"dirty",
+	"dizzy",
+	"dreamy",
+	"dry",
+	"dull",
+	"dusty",
+	"eager",
+	"early",
+	"earnest",
+	"easy",
+	"elastic",
+	"elder",
+	"electric",
+	"elegant",
+	"empty",
+	"endless",
+	"epic",
+	"equal",
+	"even",
+	"exact",
+	"exotic",
+	"faded",
+	"faint",
+	"fair",
+	"famous",
+	"fancy",
+	"far",
+	"fast",
+	"fearless",
+	"feisty",
+	"fierce",
+	"fine",
+	"firm",
+	"fixed",
+	"flat",
+	"fluffy",
+	"fond",
+	"formal",
+	"fragile",
+	"frank",
+	"free",
+	"fresh",
+	"frosty",
+	"frozen",
+	"frugal",
+	"full",
+	"funny",
+	"fuzzy",
+	"gentle",
+	"giant",
+	"giddy",
+	"gifted",
+	"glad",
+	"glassy",
+	"gloomy",
+	"glossy",
+	"golden",
+	"good",
+	"graceful",
+	"grand",
+	"grassy",
+	"gray",
+	"great",
+	"green",
+	"grim",
+	"gritty",
+	"happy",
+	"hardy",
+	"harsh",
+	"hasty",
+	"hazy",
+	"healthy",
+	"heavy",
+	"helpful",
+	"hidden",
+	"high",
+	"hollow",
+	"honest",
+	"hot",
+	"huge",
+	"humble",
+	"hungry",
+	"icy",
+	"ideal",
+	"idle",
+	"jolly",
+	"jumbo",
+	"keen",
+	"kind",
+	"large",
+	"lavish",
+	"lazy",
+	"lean",
+	"light",
+	"lively",
+	"livid",
+	"local",
+	"lofty",
+	"lone",
+	"long",
+	"loud",
+	"lovely",
+	"loyal",
+	"lucky",
+	"lunar",
+	"magic",
+	"major",
+	"mellow",
+	"merry",
+	"mighty",
+	"mild",
+	"misty",
+	"modern",
+	"modest",
+	"moist",
+	"moral",
+	"muddy",
+	"murky",
+	"mute",
+	"narrow",
+	"native",
+	"neat",
+	"nervous",
+	"new",
+	"nimble",
+	"noble",
+	"noisy",
+	"normal",
+	"novel",
+	"nutty",
+	"oblong",
+	"odd",
+	"old",
+	"open",
+	"orange",
+	"oval",
+	"pale",
+	"perfect",
+	"plain",
+	"plucky",
+	"polite",
+	"poor",
+	"proud",
+	"purple",
+	"quick",
+	"quiet",
+	"radiant",
+	"rapid",
+	"rare",
+	"raw",
+	"ready",
+	"regal",
+	"rich",
+	"rigid",
+	"ripe",
+	"robust",
+	"rosy",
+	"rough",
+	"round",
+	"royal",
+	"rugged",
+	"rustic",
+	"sacred",
+	"salty",
+	"sandy",
+	"scarlet",
+	"secret",
+	"serene",
+	"shaggy",
+	"sharp",
+	"shiny",
+	"short",
+	"shy",
+	"silent",
+	"silky",
+	"silly",
+	"simple",
+	"sleek",
+	"slim",
+	"slow",
+	"small",
+	"smart",
+	"smooth",
+	"snowy",
+	"soft",
+	"solar",
+	"solid",
+	"sour",
+	"spicy",
+	"spry",
+	"steady",
+	"steep",
+	"stiff",
+	"stormy",
+	"strong",
+	"sturdy",
+	"sunny",
+	"super",
+	"sweet",
+	"swift",
+	"tall",
+	"tame",
+	"tender",
+	"tidy",
+	"tiny",
+	"tough",
+	"tranquil",
+	"tropical",
+	"true",
+	"vast",
+	"velvet",
+	"vivid",
+	"warm",
+	"wary",
+	"wavy",
+	"weary",
+	"wide",
+	"wild",
+	"windy",
+	"wise",
+	"witty",
+	"young",
+	"zany",
+	"zesty",
+}
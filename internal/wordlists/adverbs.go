@@ -0,0 +1,68 @@
+package wordlists
+
+var Adverbs = []string{
+	"boldly",
+	"bravely",
+	"briskly",
+	"calmly",
+	"carefully",
+	"cheerfully",
+	"clearly",
+	"closely",
+	"eagerly",
+	"easily",
+	"evenly",
+	"fairly",
+	"faithfully",
+	"fiercely",
+	"firmly",
+	"fondly",
+	"freely",
+	"gently",
+	"gladly",
+	"gracefully",
+	"happily",
+	"honestly",
+	"humbly",
+	"jointly",
+	"kindly",
+	"lazily",
+	"lightly",
+	"loudly",
+	"loyally",
+	"madly",
+	"merrily",
+	"mildly",
+	"neatly",
+	"nicely",
+	"noisily",
+	"openly",
+	"patiently",
+	"politely",
+	"promptly",
+	"proudly",
+	"quickly",
+	"quietly",
+	"rapidly",
+	"rarely",
+	"rudely",
+	"sadly",
+	"safely",
+	"shyly",
+	"silently",
+	"slowly",
+	"smoothly",
+	"softly",
+	"solemnly",
+	"steadily",
+	"sternly",
+	"swiftly",
+	"tenderly",
+	"tightly",
+	"truly",
+	"vastly",
+	"warmly",
+	"wildly",
+	"wisely",
+	"yearly",
+}
@@ -0,0 +1,20 @@
+package wordlists
+
+var Determiners = []string{
+	"the",
+	"this",
+	"that",
+	"every",
+	"each",
+	"my",
+	"your",
+	"our",
+	"their",
+	"his",
+	"her",
+	"one",
+	"no",
+	"any",
+	"some",
+	"another",
+}
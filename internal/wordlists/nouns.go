@@ -0,0 +1,261 @@
+package wordlists
+
+var Nouns = []string{
+	"acorn",
+	"actor",
+	"airport",
+	"album",
+	"anchor",
+	"angel",
+	"ankle",
+	"anthem",
+	"apple",
+	"apron",
+	"arch",
+	"arena",
+	"armor",
+	"arrow",
+	"artist",
+	"atlas",
+	"attic",
+	"avocado",
+	"badge",
+	"bagel",
+	"bakery",
+	"balcony",
+	"ballad",
+	"bamboo",
+	"banana",
+	"banjo",
+	"banner",
+	"barn",
+	"barrel",
+	"basket",
+	"beach",
+	"beacon",
+	"beard",
+	"beaver",
+	"bell",
+	"bench",
+	"berry",
+	"bicycle",
+	"bishop",
+	"blanket",
+	"blender",
+	"blossom",
+	"boat",
+	"bonnet",
+	"book",
+	"boulder",
+	"bowl",
+	"bracelet",
+	"branch",
+	"bread",
+	"bridge",
+	"brook",
+	"broom",
+	"bucket",
+	"buffalo",
+	"bugle",
+	"butter",
+	"button",
+	"cabin",
+	"cactus",
+	"camel",
+	"camera",
+	"candle",
+	"canoe",
+	"canyon",
+	"captain",
+	"carpet",
+	"carrot",
+	"castle",
+	"cathedral",
+	"cello",
+	"chair",
+	"chalk",
+	"cherry",
+	"chimney",
+	"circus",
+	"clarinet",
+	"cliff",
+	"clock",
+	"cloud",
+	"clover",
+	"coast",
+	"cobra",
+	"comet",
+	"compass",
+	"cookie",
+	"copper",
+	"coral",
+	"cottage",
+	"cousin",
+	"cradle",
+	"crane",
+	"crayon",
+	"cricket",
+	"crown",
+	"crystal",
+	"cupboard",
+	"curtain",
+	"cushion",
+	"daisy",
+	"desert",
+	"diamond",
+	"dolphin",
+	"donkey",
+	"dragon",
+	"drum",
+	"eagle",
+	"easel",
+	"elbow",
+	"ember",
+	"engine",
+	"falcon",
+	"feather",
+	"fence",
+	"ferry",
+	"fiddle",
+	"field",
+	"finch",
+	"forest",
+	"fossil",
+	"fountain",
+	"fox",
+	"garden",
+	"garlic",
+	"geyser",
+	"giraffe",
+	"glacier",
+	"goblet",
+	"goose",
+	"granite",
+	"guitar",
+	"hammer",
+	"harbor",
+	"harp",
+	"hawk",
+	"helmet",
+	"hermit",
+	"hill",
+	"honey",
+	"horizon",
+	"hornet",
+	"island",
+	"ivory",
+	"jacket",
+	"jaguar",
+	"jelly",
+	"jungle",
+	"kettle",
+	"kitten",
+	"koala",
+	"ladder",
+	"lagoon",
+	"lantern",
+	"lemon",
+	"library",
+	"lighthouse",
+	"lily",
+	"lizard",
+	"lobster",
+	"locket",
+	"magnet",
+	"mango",
+	"maple",
+	"marble",
+	"meadow",
+	"melon",
+	"mirror",
+	"monkey",
+	"moose",
+	"mountain",
+	"muffin",
+	"museum",
+	"needle",
+	"nugget",
+	"oasis",
+	"ocean",
+	"octopus",
+	"olive",
+	"orchard",
+	"otter",
+	"owl",
+	"paddle",
+	"palace",
+	"panda",
+	"parrot",
+	"peach",
+	"pebble",
+	"pelican",
+	"pencil",
+	"pepper",
+	"piano",
+	"pillow",
+	"pilot",
+	"pirate",
+	"planet",
+	"plum",
+	"pocket",
+	"pony",
+	"potato",
+	"pumpkin",
+	"puzzle",
+	"quilt",
+	"rabbit",
+	"radish",
+	"rainbow",
+	"raven",
+	"ribbon",
+	"river",
+	"robot",
+	"rocket",
+	"saddle",
+	"sailor",
+	"salmon",
+	"sandal",
+	"satchel",
+	"scarf",
+	"scooter",
+	"shadow",
+	"shovel",
+	"spider",
+	"sponge",
+	"squirrel",
+	"stable",
+	"statue",
+	"summit",
+	"sunset",
+	"swan",
+	"table",
+	"teapot",
+	"temple",
+	"thistle",
+	"thunder",
+	"tiger",
+	"toaster",
+	"tomato",
+	"tortoise",
+	"tower",
+	"tractor",
+	"trumpet",
+	"tulip",
+	"tunnel",
+	"turtle",
+	"umbrella",
+	"valley",
+	"vase",
+	"violin",
+	"volcano",
+	"wagon",
+	"walnut",
+	"walrus",
+	"whale",
+	"whistle",
+	"willow",
+	"window",
+	"wizard",
+	"wolf",
+	"zebra",
+}
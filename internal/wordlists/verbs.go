@@ -0,0 +1,302 @@
+package wordlists
+
+var Verbs = []string{
+	"accept",
+	"adapt",
+	"admire",
+	"advise",
+	"agree",
+	"allow",
+	"amuse",
+	"answer",
+	"appear",
+	"applaud",
+	"arrange",
+	"arrive",
+	"attach",
+	"attend",
+	"avoid",
+	"bake",
+	"balance",
+	"bang",
+	"bathe",
+	"battle",
+	"beam",
+	"beg",
+	"behave",
+	"belong",
+	"bless",
+	"blink",
+	"blush",
+	"boast",
+	"boil",
+	"bolt",
+	"borrow",
+	"bounce",
+	"bow",
+	"brake",
+	"breathe",
+	"bring",
+	"brush",
+	"bubble",
+	"build",
+	"bump",
+	"buzz",
+	"calculate",
+	"call",
+	"carry",
+	"carve",
+	"catch",
+	"celebrate",
+	"chase",
+	"cheer",
+	"chew",
+	"chop",
+	"clap",
+	"clean",
+	"climb",
+	"collect",
+	"comb",
+	"command",
+	"compare",
+	"complain",
+	"compute",
+	"cook",
+	"copy",
+	"count",
+	"crawl",
+	"cross",
+	"crush",
+	"cry",
+	"curl",
+	"cycle",
+	"dance",
+	"dare",
+	"decide",
+	"deliver",
+	"depend",
+	"describe",
+	"design",
+	"dig",
+	"discover",
+	"dive",
+	"divide",
+	"doodle",
+	"drag",
+	"draw",
+	"dream",
+	"drift",
+	"drink",
+	"drive",
+	"drop",
+	"dust",
+	"earn",
+	"echo",
+	"embrace",
+	"enjoy",
+	"enter",
+	"escape",
+	"examine",
+	"excite",
+	"explain",
+	"explore",
+	"fasten",
+	"fetch",
+	"fill",
+	"find",
+	"fix",
+	"flash",
+	"float",
+	"flow",
+	"fly",
+	"fold",
+	"follow",
+	"forgive",
+	"frame",
+	"gather",
+	"gaze",
+	"giggle",
+	"glide",
+	"glow",
+	"grab",
+	"greet",
+	"grin",
+	"grow",
+	"guard",
+	"guess",
+	"guide",
+	"hammer",
+	"handle",
+	"hang",
+	"happen",
+	"harvest",
+	"hatch",
+	"hike",
+	"hop",
+	"hover",
+	"hug",
+	"hum",
+	"hunt",
+	"hurry",
+	"imagine",
+	"impress",
+	"improve",
+	"include",
+	"inspect",
+	"invent",
+	"invite",
+	"itch",
+	"jog",
+	"join",
+	"joke",
+	"judge",
+	"juggle",
+	"jump",
+	"kick",
+	"kneel",
+	"knit",
+	"knock",
+	"label",
+	"land",
+	"laugh",
+	"launch",
+	"lead",
+	"lean",
+	"learn",
+	"lift",
+	"limp",
+	"listen",
+	"load",
+	"lock",
+	"march",
+	"marry",
+	"measure",
+	"melt",
+	"mend",
+	"mix",
+	"mourn",
+	"move",
+	"nest",
+	"nod",
+	"notice",
+	"obey",
+	"offer",
+	"open",
+	"order",
+	"paddle",
+	"paint",
+	"pass",
+	"pause",
+	"pedal",
+	"perform",
+	"pinch",
+	"plant",
+	"play",
+	"plead",
+	"plow",
+	"point",
+	"polish",
+	"pounce",
+	"pour",
+	"praise",
+	"pray",
+	"preach",
+	"print",
+	"promise",
+	"pull",
+	"pump",
+	"punch",
+	"push",
+	"puzzle",
+	"quiz",
+	"race",
+	"rattle",
+	"reach",
+	"read",
+	"relax",
+	"remember",
+	"repair",
+	"repeat",
+	"rescue",
+	"rest",
+	"return",
+	"ride",
+	"ring",
+	"roar",
+	"roll",
+	"row",
+	"rush",
+	"sail",
+	"scare",
+	"scatter",
+	"scribble",
+	"search",
+	"shave",
+	"shine",
+	"shiver",
+	"shout",
+	"sing",
+	"skate",
+	"sketch",
+	"ski",
+	"skip",
+	"slide",
+	"smile",
+	"sneeze",
+	"sniff",
+	"snore",
+	"soar",
+	"solve",
+	"sparkle",
+	"spin",
+	"splash",
+	"sprint",
+	"squeak",
+	"stamp",
+	"stare",
+	"steer",
+	"stir",
+	"stretch",
+	"stroll",
+	"study",
+	"surf",
+	"swim",
+	"swing",
+	"tap",
+	"teach",
+	"tease",
+	"thank",
+	"tickle",
+	"tiptoe",
+	"toss",
+	"trace",
+	"travel",
+	"trot",
+	"tumble",
+	"twirl",
+	"type",
+	"unlock",
+	"unpack",
+	"vanish",
+	"visit",
+	"wade",
+	"wander",
+	"warn",
+	"wash",
+	"watch",
+	"wave",
+	"weave",
+	"whisper",
+	"whistle",
+	"wink",
+	"wish",
+	"wobble",
+	"wonder",
+	"worry",
+	"wrap",
+	"wrestle",
+	"write",
+	"yawn",
+	"yell",
+	"zoom",
+}